@@ -0,0 +1,135 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// AK represents a SCCP Message Data acknowledgement (AK).
+type AK struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	ReceiveSequenceNumber     *params.ReceiveSequenceNumber
+	Credit                    *params.Credit
+}
+
+// NewAK creates a new AK.
+// P(R) is given in 0-127, and shifted to the left by 1 in ReceiveSequenceNumber.
+func NewAK(dlr uint32, pr, credit uint8) *AK {
+	return &AK{
+		Type:                      MsgTypeAK,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		ReceiveSequenceNumber:     params.NewReceiveSequenceNumber(pr << 1),
+		Credit:                    params.NewCredit(credit),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a AK instance.
+func (a *AK) MarshalBinary() ([]byte, error) {
+	b := make([]byte, a.MarshalLen())
+	if err := a.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (a *AK) MarshalTo(b []byte) error {
+	if len(b) < a.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(a.Type)
+
+	n := 1
+	m, err := a.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = a.ReceiveSequenceNumber.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := a.Credit.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseAK decodes given byte sequence as a SCCP AK.
+func ParseAK(b []byte) (*AK, error) {
+	a := &AK{}
+	if err := a.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP AK.
+func (a *AK) UnmarshalBinary(b []byte) error {
+	if len(b) < 6 {
+		return io.ErrUnexpectedEOF
+	}
+
+	a.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	a.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	a.ReceiveSequenceNumber, n, err = params.ParseReceiveSequenceNumber(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	a.Credit, _, err = params.ParseCredit(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (a *AK) MarshalLen() int {
+	return 6 // MsgType + DestinationLocalReference + ReceiveSequenceNumber + Credit
+}
+
+// String returns the AK values in human readable format.
+func (a *AK) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, ReceiveSequenceNumber: %s, Credit: %s}",
+		a.Type,
+		a.DestinationLocalReference,
+		a.ReceiveSequenceNumber,
+		a.Credit,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (a *AK) MessageType() MsgType {
+	return MsgTypeAK
+}
+
+// MessageTypeName returns the Message Type in string.
+func (a *AK) MessageTypeName() string {
+	return a.MessageType().String()
+}
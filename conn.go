@@ -0,0 +1,349 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Addr is the address of the SCCP connection, which implements net.Addr.
+type Addr struct {
+	*params.PartyAddress
+}
+
+// Network returns the name of the network, "sccp".
+func (a *Addr) Network() string {
+	return "sccp"
+}
+
+// String returns the party address in string.
+func (a *Addr) String() string {
+	if a == nil || a.PartyAddress == nil {
+		return "<nil>"
+	}
+	return a.PartyAddress.String()
+}
+
+// Conn is a stream over the connection-oriented SCCP in protocol class 2, which
+// implements net.Conn.
+//
+// The data given to Write are sent in DT1, segmented if longer than 255 octets.
+// As the boundaries of the data are kept in SCCP, Read returns the data received in
+// a Write of the peer, or the rest of it if b is shorter than that.
+type Conn struct {
+	conn          *Connection
+	local, remote *Addr
+
+	mu      sync.Mutex
+	buf     []byte
+	rd, wd  *deadline
+	closeMu sync.Mutex
+}
+
+// Dial establishes a connection to the Called Party Address over the transport,
+// and returns it as a Conn.
+//
+// The Calling Party Address is given to the peer in CR if not nil. The returned error is
+// *ConnectionRefusedError if the peer refuses the connection.
+func Dial(ctx context.Context, transport *Node, cdpa, cgpa *params.PartyAddress) (*Conn, error) {
+	c, err := transport.Connect(ctx, 2, cdpa, cgpa, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConn(c, &Addr{cgpa}, &Addr{cdpa}), nil
+}
+
+func newConn(c *Connection, local, remote *Addr) *Conn {
+	return &Conn{
+		conn:   c,
+		local:  local,
+		remote: remote,
+		rd:     newDeadline(),
+		wd:     newDeadline(),
+	}
+}
+
+// Connection returns the underlying Connection.
+func (c *Conn) Connection() *Connection {
+	return c.conn
+}
+
+// Read reads the data received from the peer.
+// It returns io.EOF when the connection is released by the peer.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.buf) == 0 {
+		ctx, cancel := c.rd.context()
+		defer cancel()
+
+		data, err := c.conn.Receive(ctx)
+		if err != nil {
+			return 0, c.error(ctx, err)
+		}
+		c.buf = data
+	}
+
+	n := copy(b, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// Write sends the data to the peer.
+func (c *Conn) Write(b []byte) (int, error) {
+	ctx, cancel := c.wd.context()
+	defer cancel()
+
+	if err := c.conn.Send(ctx, b); err != nil {
+		return 0, c.error(ctx, err)
+	}
+	return len(b), nil
+}
+
+// Close releases the connection by sending RLSD, and blocks until RLC is received.
+// It returns nil if the connection is already released.
+func (c *Conn) Close() error {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	select {
+	case <-c.conn.Done():
+		return nil
+	default:
+	}
+
+	err := c.conn.Disconnect(context.Background(), params.ReleaseCauseEndUserOriginated)
+	if err != nil {
+		select {
+		case <-c.conn.Done():
+			// released by the peer at the same time.
+			return nil
+		default:
+		}
+	}
+	return err
+}
+
+// LocalAddr returns the local party address. The PartyAddress is nil if not given in Dial.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr returns the party address of the peer. The PartyAddress is nil if the
+// Calling Party Address is not given by the peer.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.rd.set(t)
+	c.wd.set(t)
+	return nil
+}
+
+// SetReadDeadline sets the deadline for the Read calls, including the one blocked.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.rd.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for the Write calls, including the one blocked.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.wd.set(t)
+	return nil
+}
+
+// error converts the error from the Connection to the one expected for net.Conn.
+func (c *Conn) error(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return os.ErrDeadlineExceeded
+	}
+
+	var rerr *ConnectionReleasedError
+	if errors.As(err, &rerr) && rerr.Cause == params.ReleaseCauseEndUserOriginated {
+		return io.EOF
+	}
+	return err
+}
+
+// deadline is the deadline of the blocking operations, which can be changed while
+// the operations are blocked.
+type deadline struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{done: make(chan struct{})}
+}
+
+// set sets the deadline. The zero value means no deadline.
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil && !d.timer.Stop() {
+		// wait for the timer already fired to close the channel, so that it does not
+		// close the renewed one.
+		<-d.done
+	}
+	d.timer = nil
+
+	var expired bool
+	select {
+	case <-d.done:
+		expired = true
+	default:
+	}
+
+	if t.IsZero() {
+		if expired {
+			d.done = make(chan struct{})
+		}
+		return
+	}
+
+	if dur := time.Until(t); dur > 0 {
+		if expired {
+			d.done = make(chan struct{})
+		}
+		done := d.done
+		d.timer = time.AfterFunc(dur, func() { close(done) })
+		return
+	}
+	if !expired {
+		close(d.done)
+	}
+}
+
+// context returns the context that is done when the deadline expires.
+func (d *deadline) context() (context.Context, context.CancelFunc) {
+	d.mu.Lock()
+	done := d.done
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// listenQueueSize is the number of the connections that can wait for Accept.
+const listenQueueSize = 16
+
+// Listener accepts the connections to a local subsystem, which implements net.Listener.
+type Listener struct {
+	node    *Node
+	addr    *Addr
+	handler *ConnectHandler
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan *ConnectIndication
+	done   chan struct{}
+}
+
+// Listen starts accepting the connections to the local subsystem over the transport.
+// The ConnectHandler registered for the subsystem is replaced, and it is unregistered
+// on Close unless replaced by another one after Listen.
+func Listen(transport *Node, ssn uint8) (*Listener, error) {
+	if ssn == 0 {
+		return nil, errors.New("sccp: subsystem number must not be 0")
+	}
+
+	l := &Listener{
+		node: transport,
+		addr: &Addr{params.NewPartyAddressWithVariant(
+			transport.Variant, params.PCodeCalledPartyAddress,
+			params.NewAddressIndicatorWithVariant(transport.Variant, true, true, true, params.GTINoGT),
+			transport.PointCode.Value, ssn, nil,
+		)},
+		queue: make(chan *ConnectIndication, listenQueueSize),
+		done:  make(chan struct{}),
+	}
+
+	l.handler = transport.registerConnectHandler(ssn, func(ind *ConnectIndication) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.closed {
+			_ = ind.Refuse(context.Background(), params.RefusalCauseUnequippedUser)
+			return
+		}
+
+		select {
+		case l.queue <- ind:
+		default:
+			_ = ind.Refuse(context.Background(), params.RefusalCauseEndUserCongestion)
+		}
+	})
+	return l, nil
+}
+
+// Accept waits for the CR to the subsystem and answers it with CC.
+func (l *Listener) Accept() (net.Conn, error) {
+	for {
+		var ind *ConnectIndication
+		select {
+		case ind = <-l.queue:
+		case <-l.done:
+			return nil, net.ErrClosed
+		}
+
+		c, err := ind.Accept(context.Background(), nil)
+		if err != nil {
+			// try the next one as the failure is specific to the connection.
+			logf("failed to accept connection from %s: %s", ind.OPC, err)
+			continue
+		}
+
+		return newConn(c, &Addr{ind.CalledPartyAddress}, &Addr{ind.CallingPartyAddress}), nil
+	}
+}
+
+// Close stops accepting the connections, and refuses the ones waiting for Accept.
+// The connections already accepted are not affected.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	close(l.done)
+	l.node.unregisterConnectHandler(l.addr.SubsystemNumber, l.handler)
+
+	for {
+		select {
+		case ind := <-l.queue:
+			_ = ind.Refuse(context.Background(), params.RefusalCauseUnequippedUser)
+		default:
+			return nil
+		}
+	}
+}
+
+// Addr returns the address of the local subsystem.
+func (l *Listener) Addr() net.Addr {
+	return l.addr
+}
@@ -0,0 +1,173 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestConn(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a, b := connectedNodes(ctx, t, sccp.DefaultTimers)
+
+	l, err := sccp.Listen(b, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- c
+	}()
+
+	ca, err := sccp.Dial(ctx, a, ssnAddr(2, 8), ssnAddr(1, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb := <-accepted
+	if cb == nil {
+		t.FailNow()
+	}
+	verify.Values(t, "RemoteAddr SSN", cb.RemoteAddr().(*sccp.Addr).SubsystemNumber, uint8(6))
+
+	t.Run("segmented write", func(t *testing.T) {
+		// segmented into 3 DT1s and reassembled.
+		data := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 150)
+		if _, err := ca.Write(data); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([]byte, len(data))
+		if _, err := io.ReadFull(cb, got); err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "data", got, data)
+	})
+
+	t.Run("read deadline", func(t *testing.T) {
+		if err := cb.SetReadDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cb.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("expected ErrDeadlineExceeded, got %v", err)
+		}
+		if err := cb.SetReadDeadline(time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		if err := ca.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cb.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+			t.Fatalf("expected EOF, got %v", err)
+		}
+		if err := cb.Close(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("closed listener", func(t *testing.T) {
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.Accept(); !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("expected ErrClosed, got %v", err)
+		}
+
+		_, err := sccp.Dial(ctx, a, ssnAddr(2, 8), nil)
+		var rerr *sccp.ConnectionRefusedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionRefusedError, got %v", err)
+		}
+		verify.Values(t, "RefusalCause", rerr.Cause, params.RefusalCauseUnequippedUser)
+	})
+
+	t.Run("replaced handler", func(t *testing.T) {
+		l, err := sccp.Listen(b, 9)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the handler registered after Listen is kept on Close.
+		b.RegisterConnectHandler(9, func(ind *sccp.ConnectIndication) {
+			if err := ind.Refuse(ctx, params.RefusalCauseEndUserCongestion); err != nil {
+				t.Error(err)
+			}
+		})
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = sccp.Dial(ctx, a, ssnAddr(2, 9), nil)
+		var rerr *sccp.ConnectionRefusedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionRefusedError, got %v", err)
+		}
+		verify.Values(t, "RefusalCause", rerr.Cause, params.RefusalCauseEndUserCongestion)
+	})
+}
+
+func TestListenVariant(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sapA, sapB := mtp.NewPipe()
+	defer sapA.Close()
+
+	v := params.VariantANSI
+	a := sccp.NewNode(v, v.NewPointCode(0x010203), sapA)
+	b := sccp.NewNode(v, v.NewPointCode(0x040506), sapB)
+	go func() { _ = a.Serve(ctx) }()
+	go func() { _ = b.Serve(ctx) }()
+
+	l, err := sccp.Listen(b, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	addr := l.Addr().(*sccp.Addr)
+	verify.Values(t, "SignalingPointCode", addr.SignalingPointCode, v.NewPointCode(0x040506))
+	verify.Values(t, "Indicator", addr.Indicator, params.NewAddressIndicatorWithVariant(v, true, true, true, params.GTINoGT))
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- c
+	}()
+
+	c, err := sccp.Dial(ctx, a, addr.PartyAddress, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if cb := <-accepted; cb != nil {
+		defer cb.Close()
+	}
+}
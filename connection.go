@@ -0,0 +1,997 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Timers is a set of the timers used in the connection-oriented procedures.
+// See Q.714 Annex C for the details.
+type Timers struct {
+	// ConnEst is T(conn est), waiting for the connection confirm after sending CR.
+	ConnEst time.Duration
+	// IAS is T(ias), the send inactivity timer. IT is sent when no message is sent for the period.
+	IAS time.Duration
+	// IAR is T(iar), the receive inactivity timer. The connection is released when no
+	// message is received for the period.
+	IAR time.Duration
+	// Rel is T(rel), waiting for the release complete after sending RLSD.
+	Rel time.Duration
+	// RepeatRel is T(repeat rel), the interval to repeat RLSD after T(rel) expires.
+	RepeatRel time.Duration
+	// Int is T(int), waiting for the release complete after T(rel) expires.
+	// The local reference is released when it expires.
+	Int time.Duration
+	// Freeze is the period that the released local reference is not reused.
+	Freeze time.Duration
+	// Reset is T(reset), waiting for the reset confirm after sending RSR.
+	// The connection is released when it expires.
+	Reset time.Duration
+}
+
+// DefaultTimers is the default values of the Timers.
+var DefaultTimers = Timers{
+	ConnEst:   1 * time.Minute,
+	IAS:       5 * time.Minute,
+	IAR:       11 * time.Minute,
+	Rel:       10 * time.Second,
+	RepeatRel: 10 * time.Second,
+	Int:       1 * time.Minute,
+	Freeze:    1 * time.Minute,
+	Reset:     20 * time.Second,
+}
+
+// maxLocalReference is the maximum value of the 24-bit local reference.
+const maxLocalReference = 1<<24 - 1
+
+// maxSegmentLen is the maximum length of the data in a DT1 or DT2.
+const maxSegmentLen = 255
+
+// maxReassembledLen is the maximum length of the data reassembled from the segments
+// with the M (more data) bit set.
+const maxReassembledLen = 64 << 10
+
+// dataQueueSize is the number of the N-DATA indications that can be queued
+// before Receive is called in protocol class 2. The Connection is released when
+// the peer sends more. In protocol class 3, the queue is limited by the window instead.
+const dataQueueSize = 64
+
+// backgroundSendTimeout is the time limit to send the messages that are not requested
+// by the user, e.g., on timer expiry.
+const backgroundSendTimeout = 10 * time.Second
+
+// connState is the state of the connection section.
+type connState uint8
+
+const (
+	connStateOutgoing          connState = iota // CR sent
+	connStateOutgoingAborted                    // CR sent, but the user gave up
+	connStateIncoming                           // CR received
+	connStateActive                             // data transfer
+	connStateResetting                          // RSR sent
+	connStateDisconnectPending                  // RLSD sent
+	connStateClosed
+)
+
+// Connection is a connection section of the connection-oriented SCCP (protocol class 2
+// or 3), which provides the N-DATA and N-DISCONNECT primitives to the user.
+//
+// In protocol class 3, the data are sent in DT2 within the window negotiated with
+// Credit in CR and CC, and the reset procedure is started on the sequence error.
+//
+// A Connection is created by Node.Connect (N-CONNECT request), or by accepting the
+// ConnectIndication delivered to the ConnectHandler (N-CONNECT indication).
+type Connection struct {
+	node *Node
+
+	localRef  uint32
+	remoteRef uint32
+	remotePC  params.PointCode
+	sls       uint8
+	class     int
+	cdpa      *params.PartyAddress
+	cgpa      *params.PartyAddress
+
+	sendMu  sync.Mutex // serializes the segments of the data in Send
+	flushMu sync.Mutex // keeps the messages in out in order while being sent
+
+	mu         sync.Mutex
+	out        []*outgoing
+	state      connState
+	confirmed  chan struct{}
+	released   chan struct{}
+	err        error
+	data       [][]byte
+	readable   chan struct{}
+	partial    []byte
+	resets     chan params.ResetCauseValue
+	relCause   params.ReleaseCauseValue
+	seq        *sequence
+	tConnEst   *time.Timer
+	tIAS, tIAR *time.Timer
+	tRel, tInt *time.Timer
+	tReset     *time.Timer
+}
+
+// outgoing is a message to the peer, which is queued while c.mu is held and sent
+// after it is released so that a slow MTP does not block the other operations.
+type outgoing struct {
+	m   Message
+	dpc params.PointCode
+	err error
+}
+
+// ConnectHandler handles the N-CONNECT indication delivered to a local subsystem.
+//
+// The handler should either Accept or Refuse the ConnectIndication. It can be done
+// after the handler returns, but the Node does not handle the subsequent messages
+// until the handler returns.
+type ConnectHandler func(ind *ConnectIndication)
+
+// ConnectIndication is the N-CONNECT indication.
+type ConnectIndication struct {
+	OPC                 params.PointCode
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                []byte
+
+	conn *Connection
+}
+
+// Accept accepts the connection by sending CC, i.e., N-CONNECT response.
+func (ci *ConnectIndication) Accept(ctx context.Context, data []byte) (*Connection, error) {
+	c := ci.conn
+	c.mu.Lock()
+	if c.state != connStateIncoming {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("sccp: connection %d is not waiting for response", c.localRef)
+	}
+
+	var opts []params.Parameter
+	if c.class == 3 {
+		opts = append(opts, params.NewCreditOptional(c.seq.recvCredit))
+	}
+	if len(data) > 0 {
+		opts = append(opts, params.NewDataOptional(data))
+	}
+	cc := c.send(NewCC(c.remoteRef, c.localRef, c.class, opts...))
+	c.activate()
+	c.unlock(ctx)
+
+	if cc.err != nil {
+		c.mu.Lock()
+		c.release(&ConnectionRefusedError{Cause: params.RefusalCauseSCCPFailure})
+		c.mu.Unlock()
+		return nil, cc.err
+	}
+	return c, nil
+}
+
+// Refuse refuses the connection by sending CREF, i.e., N-DISCONNECT request
+// in the connection establishment phase.
+func (ci *ConnectIndication) Refuse(ctx context.Context, cause params.RefusalCauseValue) error {
+	c := ci.conn
+	c.mu.Lock()
+	if c.state != connStateIncoming {
+		c.mu.Unlock()
+		return fmt.Errorf("sccp: connection %d is not waiting for response", c.localRef)
+	}
+
+	c.release(&ConnectionRefusedError{Cause: cause})
+	cref := c.send(NewCREF(c.remoteRef, cause))
+	c.unlock(ctx)
+	return cref.err
+}
+
+// RegisterConnectHandler registers the ConnectHandler for the local subsystem.
+// The CR to the subsystem without the ConnectHandler, or with nil, is refused.
+func (n *Node) RegisterConnectHandler(ssn uint8, h ConnectHandler) {
+	n.registerConnectHandler(ssn, h)
+}
+
+// registerConnectHandler registers the ConnectHandler, and returns the pointer to it,
+// which identifies the registration in unregisterConnectHandler.
+func (n *Node) registerConnectHandler(ssn uint8, h ConnectHandler) *ConnectHandler {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if h == nil {
+		delete(n.connHandlers, ssn)
+		return nil
+	}
+	n.connHandlers[ssn] = &h
+	return &h
+}
+
+// unregisterConnectHandler unregisters the ConnectHandler for the subsystem only if
+// it is still the one registered with registerConnectHandler.
+func (n *Node) unregisterConnectHandler(ssn uint8, h *ConnectHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.connHandlers[ssn] == h {
+		delete(n.connHandlers, ssn)
+	}
+}
+
+// Connect establishes a new connection to the Called Party Address, i.e., N-CONNECT request.
+//
+// It sends CR with the Calling Party Address and the data if given, and blocks until
+// CC is received. If CREF is received or T(conn est) expires, the returned error is
+// *ConnectionRefusedError. The protocol class must be 2 or 3, and the protocol class 3
+// may be lowered to 2 by the peer.
+func (n *Node) Connect(ctx context.Context, pcls int, cdpa, cgpa *params.PartyAddress, data []byte) (*Connection, error) {
+	if pcls != 2 && pcls != 3 {
+		return nil, fmt.Errorf("sccp: unsupported protocol class: %d", pcls)
+	}
+
+	c := n.newConnection(pcls, connStateOutgoing, n.Credit)
+	c.cdpa, c.cgpa = cdpa, cgpa
+	if err := n.allocate(c); err != nil {
+		return nil, err
+	}
+
+	routed, dpc, _, err := n.route(cdpa, c.sls)
+	if err != nil {
+		n.free(c.localRef)
+		return nil, err
+	}
+	if dpc == nil {
+		dpc = &n.PointCode
+	}
+	c.remotePC = *dpc
+
+	var opts []params.Parameter
+	if pcls == 3 {
+		opts = append(opts, params.NewCreditOptional(c.seq.recvCredit))
+	}
+	if cgpa != nil {
+		opts = append(opts, params.NewPartyAddressOptionalWithVariant(
+			n.Variant, params.PCodeCallingPartyAddress,
+			cgpa.Indicator, cgpa.SignalingPointCode.Value, cgpa.SubsystemNumber, cgpa.GlobalTitle,
+		))
+	}
+	if len(data) > 0 {
+		opts = append(opts, params.NewDataOptional(data))
+	}
+
+	c.mu.Lock()
+	cr := c.send(NewCR(c.localRef, pcls, routed, opts...))
+	c.startTimer(&c.tConnEst, n.Timers.ConnEst, func() {
+		c.release(&ConnectionRefusedError{Cause: params.RefusalCauseExpirationOfTheConnectionEstablishmentTimer})
+	})
+	c.unlock(ctx)
+
+	if cr.err != nil {
+		c.mu.Lock()
+		c.release(cr.err)
+		c.mu.Unlock()
+		return nil, cr.err
+	}
+
+	select {
+	case <-c.confirmed:
+		return c, nil
+	case <-c.released:
+		return nil, c.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		switch c.state {
+		case connStateOutgoing:
+			// the connection is released when CC is received or T(conn est) expires.
+			c.state = connStateOutgoingAborted
+		case connStateActive:
+			c.disconnect(params.ReleaseCauseEndUserOriginated)
+		}
+		bctx, cancel := backgroundContext()
+		defer cancel()
+		c.unlock(bctx)
+		return nil, ctx.Err()
+	}
+}
+
+// LocalReference returns the local reference number of the Connection.
+func (c *Connection) LocalReference() uint32 {
+	return c.localRef
+}
+
+// RemoteReference returns the local reference number of the peer.
+func (c *Connection) RemoteReference() uint32 {
+	return c.remoteRef
+}
+
+// RemotePC returns the point code of the peer.
+func (c *Connection) RemotePC() params.PointCode {
+	return c.remotePC
+}
+
+// ProtocolClass returns the protocol class of the Connection.
+func (c *Connection) ProtocolClass() int {
+	return c.class
+}
+
+// Done returns a channel that is closed when the Connection is released.
+func (c *Connection) Done() <-chan struct{} {
+	return c.released
+}
+
+// Send sends the data in DT1, or DT2 in protocol class 3, i.e., N-DATA request.
+//
+// The data longer than 255 octets are segmented into the multiple messages with the
+// M (more data) bit set, which are reassembled into the original data by the peer.
+// In protocol class 3, it blocks while the sending window is closed or the
+// Connection is being reset.
+func (c *Connection) Send(ctx context.Context, data []byte) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	for {
+		seg, more := data, false
+		if len(seg) > maxSegmentLen {
+			seg, more = data[:maxSegmentLen], true
+		}
+
+		c.mu.Lock()
+		var dt *outgoing
+		var err error
+		if c.class == 3 {
+			dt, err = c.sendDT2(ctx, seg, more)
+		} else if c.state != connStateActive {
+			err = c.closedError()
+		} else {
+			dt = c.send(NewDT1(c.remoteRef, more, seg))
+		}
+		c.unlock(ctx)
+
+		if err != nil {
+			return err
+		}
+		if dt.err != nil {
+			return dt.err
+		}
+
+		if !more {
+			return nil
+		}
+		data = data[maxSegmentLen:]
+	}
+}
+
+// Receive blocks until the data is received, i.e., N-DATA indication.
+// The segmented data are returned after reassembled.
+//
+// When the Connection is released, the data already received are returned first,
+// and then the error that caused the release, e.g., *ConnectionReleasedError.
+// In protocol class 3, *ConnectionResetError is returned when the Connection is reset,
+// i.e., N-RESET indication, and the Connection can still be used after that. The data
+// not received before the reset are discarded, and the DT2 are acknowledged as the
+// data are received so that the peer does not send more than the window.
+func (c *Connection) Receive(ctx context.Context) ([]byte, error) {
+	for {
+		c.mu.Lock()
+		if len(c.data) > 0 {
+			d := c.data[0]
+			c.data = c.data[1:]
+			if c.class == 3 && c.state == connStateActive && c.seq.held > 0 {
+				c.seq.held--
+				if c.seq.shouldAck() {
+					c.sendAK()
+				}
+			}
+			c.unlock(ctx)
+			return d, nil
+		}
+		readable := c.readable
+		c.mu.Unlock()
+
+		select {
+		case <-readable:
+		case cause := <-c.resets:
+			return nil, &ConnectionResetError{Cause: cause}
+		case <-c.released:
+			c.mu.Lock()
+			n := len(c.data)
+			c.mu.Unlock()
+			if n == 0 {
+				return nil, c.err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Disconnect releases the Connection by sending RLSD, i.e., N-DISCONNECT request,
+// and blocks until RLC is received.
+//
+// RLSD is repeated if T(rel) expires, and the Connection is released without RLC
+// when T(int) expires.
+func (c *Connection) Disconnect(ctx context.Context, cause params.ReleaseCauseValue) error {
+	c.mu.Lock()
+	if c.state != connStateActive && c.state != connStateResetting {
+		err := c.closedError()
+		c.mu.Unlock()
+		return err
+	}
+	rlsd := c.disconnect(cause)
+	c.unlock(ctx)
+
+	if rlsd.err != nil {
+		return rlsd.err
+	}
+
+	select {
+	case <-c.released:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closedError returns the error for the operations on the Connection not in data transfer phase.
+// c.mu must be held by the caller.
+func (c *Connection) closedError() error {
+	if c.err != nil {
+		return c.err
+	}
+	return fmt.Errorf("sccp: connection %d is not active", c.localRef)
+}
+
+// handle handles the connection-oriented message for the Connection.
+// c.mu must not be held by the caller.
+func (c *Connection) handle(ctx context.Context, m Message, opc params.PointCode) {
+	c.mu.Lock()
+	if c.state != connStateOutgoing && c.state != connStateOutgoingAborted && opc.Value != c.remotePC.Value {
+		c.mu.Unlock()
+		logf("discarded %s for connection %d: unexpected OPC %s", m.MessageTypeName(), c.localRef, opc)
+		return
+	}
+
+	switch m := m.(type) {
+	case *CC:
+		if c.state != connStateOutgoing && c.state != connStateOutgoingAborted {
+			break
+		}
+		c.stopTimer(&c.tConnEst)
+		c.remoteRef = m.SourceLocalReference.Uint32()
+		c.remotePC = opc
+
+		// the protocol class proposed in CR can be lowered by the peer, but not raised.
+		pcls := m.ProtocolClass.Class()
+		if pcls < 2 || pcls > c.class {
+			logf("CC with protocol class %d for connection %d: proposed %d", pcls, c.localRef, c.class)
+			c.disconnect(params.ReleaseCauseInconsistentConnectionData)
+			break
+		}
+		c.class = pcls
+		if c.class == 3 {
+			if c.seq == nil {
+				c.seq = newSequence(c.node.Credit)
+			}
+			if m.Credit != nil {
+				c.seq.sendCredit = m.Credit.Value()
+				c.seq.recvCredit = m.Credit.Value()
+			}
+		}
+		if c.state == connStateOutgoingAborted {
+			// the user has given up waiting for CC.
+			c.disconnect(params.ReleaseCauseEndUserOriginated)
+			break
+		}
+		c.activate()
+	case *CREF:
+		if c.state == connStateOutgoing || c.state == connStateOutgoingAborted {
+			c.release(&ConnectionRefusedError{Cause: m.RefusalCause.Value()})
+		}
+	case *RLSD:
+		c.send(NewRLC(m.SourceLocalReference.Uint32(), c.localRef))
+		c.release(&ConnectionReleasedError{Cause: m.ReleaseCause.Value()})
+	case *RLC:
+		if c.state == connStateDisconnectPending {
+			c.release(c.err)
+		}
+	case *ERR:
+		c.release(&ConnectionReleasedError{Cause: params.ReleaseCauseRemoteProcedureError})
+	case *DT1:
+		if c.state != connStateActive || c.class != 2 {
+			break
+		}
+		c.received()
+		data, ok := c.reassemble(m.Data.Value(), m.SegmentingReassembling.MoreData())
+		if !ok {
+			c.disconnect(params.ReleaseCauseRemoteProcedureError)
+			break
+		}
+		if data != nil {
+			if len(c.data) >= dataQueueSize {
+				logf("connection %d: %d N-DATA indications not received, releasing", c.localRef, len(c.data))
+				c.disconnect(params.ReleaseCauseEndUserCongestion)
+				break
+			}
+			c.deliver(data)
+		}
+	case *DT2:
+		if c.state != connStateActive || c.class != 3 {
+			break
+		}
+		c.handleDT2(m)
+	case *AK:
+		if c.state != connStateActive || c.class != 3 {
+			break
+		}
+		c.handleAK(m)
+	case *RSR:
+		if c.state != connStateActive && c.state != connStateResetting {
+			break
+		}
+		if c.class != 3 {
+			c.serviceClassMismatch(m)
+			break
+		}
+		c.handleRSR(m)
+	case *RSC:
+		if c.state != connStateActive && c.state != connStateResetting {
+			break
+		}
+		if c.class != 3 {
+			c.serviceClassMismatch(m)
+			break
+		}
+		c.handleRSC()
+	case *IT:
+		if c.state != connStateActive {
+			break
+		}
+		if m.SourceLocalReference.Uint32() != c.remoteRef {
+			c.disconnect(params.ReleaseCauseInconsistentConnectionData)
+			break
+		}
+		c.received()
+	default:
+		logf("discarded %s for connection %d: not supported", m.MessageTypeName(), c.localRef)
+	}
+	c.unlock(ctx)
+}
+
+// reassemble appends the segment to the data being reassembled, and returns the
+// data when the last segment is given, or nil otherwise. It fails if the data
+// exceed maxReassembledLen, and the data being reassembled are discarded.
+// c.mu must be held by the caller.
+func (c *Connection) reassemble(seg []byte, more bool) ([]byte, bool) {
+	if len(c.partial)+len(seg) > maxReassembledLen {
+		logf("connection %d: reassembled data exceed %d octets", c.localRef, maxReassembledLen)
+		c.partial = nil
+		return nil, false
+	}
+
+	data := append(c.partial, seg...)
+	if more {
+		c.partial = data
+		return nil, true
+	}
+
+	c.partial = nil
+	if data == nil {
+		return []byte{}, true
+	}
+	return data, true
+}
+
+// deliver queues the data to be returned by Receive, i.e., N-DATA indication.
+// c.mu must be held by the caller.
+func (c *Connection) deliver(data []byte) {
+	c.data = append(c.data, data)
+	close(c.readable)
+	c.readable = make(chan struct{})
+}
+
+// activate moves the Connection to the data transfer phase.
+// c.mu must be held by the caller.
+func (c *Connection) activate() {
+	c.state = connStateActive
+	close(c.confirmed)
+	c.received()
+	c.startTimer(&c.tIAS, c.node.Timers.IAS, c.sendIT)
+}
+
+// received restarts T(iar) on receiving a message.
+// c.mu must be held by the caller.
+func (c *Connection) received() {
+	c.startTimer(&c.tIAR, c.node.Timers.IAR, func() {
+		c.disconnect(params.ReleaseCauseExpirationOfReceiveInactivityTimer)
+	})
+}
+
+// sendIT sends IT on T(ias) expiry.
+// c.mu must be held by the caller.
+func (c *Connection) sendIT() {
+	if c.state != connStateActive {
+		return
+	}
+	var it *IT
+	if c.class == 3 {
+		it = NewIT(c.remoteRef, c.localRef, c.class, c.seq.vs, c.seq.lowerRecv, false, c.seq.recvCredit)
+	} else {
+		it = NewIT(c.remoteRef, c.localRef, c.class, 0, 0, false, 0)
+	}
+	c.send(it)
+}
+
+// disconnect starts the release procedure by sending RLSD. The Connection is released
+// with *ConnectionReleasedError of the cause when RLC is received or T(int) expires.
+// c.mu must be held by the caller.
+func (c *Connection) disconnect(cause params.ReleaseCauseValue) *outgoing {
+	c.relCause = cause
+	c.err = &ConnectionReleasedError{Cause: cause}
+	c.state = connStateDisconnectPending
+	c.stopTimer(&c.tIAS)
+	c.stopTimer(&c.tIAR)
+	c.stopTimer(&c.tReset)
+
+	rlsd := c.sendRLSD()
+	c.startTimer(&c.tInt, c.node.Timers.Int, func() {
+		logf("no RLC received for connection %d, releasing", c.localRef)
+		c.release(c.err)
+	})
+	return rlsd
+}
+
+// sendRLSD sends RLSD and starts T(rel), which repeats RLSD on expiry.
+// c.mu must be held by the caller.
+func (c *Connection) sendRLSD() *outgoing {
+	rlsd := c.send(NewRLSD(c.remoteRef, c.localRef, c.relCause))
+
+	d := c.node.Timers.Rel
+	if c.tInt != nil {
+		d = c.node.Timers.RepeatRel
+	}
+	c.startTimer(&c.tRel, d, func() {
+		if c.state == connStateDisconnectPending {
+			c.sendRLSD()
+		}
+	})
+	return rlsd
+}
+
+// release releases the Connection and freezes the local reference.
+// c.mu must be held by the caller.
+func (c *Connection) release(err error) {
+	if c.state == connStateClosed {
+		return
+	}
+
+	c.state = connStateClosed
+	if c.err == nil {
+		c.err = err
+	}
+	for _, t := range []**time.Timer{&c.tConnEst, &c.tIAS, &c.tIAR, &c.tRel, &c.tInt, &c.tReset} {
+		c.stopTimer(t)
+	}
+
+	c.node.free(c.localRef)
+	close(c.released)
+}
+
+// send queues the connection-oriented message to the peer, and restarts T(ias).
+// The message is sent when c.mu is released with unlock, and the result is set to
+// the err of the returned outgoing.
+// c.mu must be held by the caller.
+func (c *Connection) send(m Message) *outgoing {
+	if c.state == connStateActive || c.state == connStateResetting {
+		c.startTimer(&c.tIAS, c.node.Timers.IAS, c.sendIT)
+	}
+
+	o := &outgoing{m: m, dpc: c.remotePC}
+	c.out = append(c.out, o)
+	return o
+}
+
+// unlock releases c.mu, and sends the messages queued while it was held.
+func (c *Connection) unlock(ctx context.Context) {
+	c.mu.Unlock()
+	c.flush(ctx)
+}
+
+// flush sends the messages queued in the order. The ones queued by the other goroutines
+// can be sent together, and the ones being sent by them are waited for.
+// c.mu must not be held by the caller.
+func (c *Connection) flush(ctx context.Context) {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	out := c.out
+	c.out = nil
+	c.mu.Unlock()
+
+	for _, o := range out {
+		if o.err = c.node.sendTo(ctx, o.dpc, c.sls, o.m); o.err != nil {
+			logf("connection %d: %s", c.localRef, o.err)
+		}
+	}
+}
+
+// backgroundContext returns the context to send the messages that are not requested
+// by the user, which is bounded so that a stuck MTP does not block them forever.
+func backgroundContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), backgroundSendTimeout)
+}
+
+// startTimer (re)starts the timer, which calls f with c.mu held on expiry.
+// The messages queued in f are sent after c.mu is released.
+// c.mu must be held by the caller.
+func (c *Connection) startTimer(p **time.Timer, d time.Duration, f func()) {
+	c.stopTimer(p)
+
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		c.mu.Lock()
+		// stopped or restarted while waiting for the lock.
+		if *p != t {
+			c.mu.Unlock()
+			return
+		}
+		*p = nil
+		f()
+
+		ctx, cancel := backgroundContext()
+		defer cancel()
+		c.unlock(ctx)
+	})
+	*p = t
+}
+
+// stopTimer stops the timer.
+// c.mu must be held by the caller.
+func (c *Connection) stopTimer(p **time.Timer) {
+	if *p != nil {
+		(*p).Stop()
+		*p = nil
+	}
+}
+
+// newConnection creates a new Connection in the state given. The credit is used only
+// in protocol class 3.
+func (n *Node) newConnection(pcls int, state connState, credit uint8) *Connection {
+	c := &Connection{
+		node:      n,
+		class:     pcls,
+		state:     state,
+		confirmed: make(chan struct{}),
+		released:  make(chan struct{}),
+		readable:  make(chan struct{}),
+		resets:    make(chan params.ResetCauseValue, 1),
+	}
+	if pcls == 3 {
+		c.seq = newSequence(credit)
+	}
+	return c
+}
+
+// allocate allocates a local reference that is neither in use nor frozen to the Connection.
+func (n *Node) allocate(c *Connection) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	for len(n.thaws) > 0 && now.After(n.thaws[0].at) {
+		delete(n.frozen, n.thaws[0].ref)
+		n.thaws = n.thaws[1:]
+	}
+
+	for i := 0; i < maxLocalReference; i++ {
+		n.lastRef = n.lastRef%maxLocalReference + 1
+		if _, ok := n.conns[n.lastRef]; ok {
+			continue
+		}
+		if _, ok := n.frozen[n.lastRef]; ok {
+			continue
+		}
+
+		c.localRef = n.lastRef
+		c.sls = uint8(n.lastRef & 0x0f)
+		n.conns[n.lastRef] = c
+		return nil
+	}
+
+	return ErrNoLocalReference
+}
+
+// free frees the local reference, which is frozen for Timers.Freeze.
+func (n *Node) free(ref uint32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.conns, ref)
+	n.frozen[ref] = struct{}{}
+	n.thaws = append(n.thaws, thaw{ref: ref, at: time.Now().Add(n.Timers.Freeze)})
+}
+
+// thaw is the time when the frozen local reference can be reused. The thaws are queued
+// in the order of the time, as the local references are frozen for the same period.
+type thaw struct {
+	ref uint32
+	at  time.Time
+}
+
+// connection returns the Connection of the local reference.
+func (n *Node) connection(ref uint32) *Connection {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.conns[ref]
+}
+
+// handleConnectionOriented handles the connection-oriented message received from opc.
+func (n *Node) handleConnectionOriented(ctx context.Context, m Message, opc params.PointCode, sls uint8) {
+	var dlr *params.LocalReference
+	switch m := m.(type) {
+	case *CR:
+		n.handleCR(ctx, m, opc, sls)
+		return
+	case *CC:
+		dlr = m.DestinationLocalReference
+	case *CREF:
+		dlr = m.DestinationLocalReference
+	case *RLSD:
+		dlr = m.DestinationLocalReference
+	case *RLC:
+		dlr = m.DestinationLocalReference
+	case *DT1:
+		dlr = m.DestinationLocalReference
+	case *DT2:
+		dlr = m.DestinationLocalReference
+	case *AK:
+		dlr = m.DestinationLocalReference
+	case *RSR:
+		dlr = m.DestinationLocalReference
+	case *RSC:
+		dlr = m.DestinationLocalReference
+	case *IT:
+		dlr = m.DestinationLocalReference
+	case *ERR:
+		dlr = m.DestinationLocalReference
+	default:
+		logf("discarded %s from %s: not supported by Node", m.MessageTypeName(), opc)
+		return
+	}
+
+	c := n.connection(dlr.Uint32())
+	if c == nil {
+		// answer RLSD for unknown connection so that the peer can release it.
+		if rlsd, ok := m.(*RLSD); ok {
+			rlc := NewRLC(rlsd.SourceLocalReference.Uint32(), rlsd.DestinationLocalReference.Uint32())
+			if err := n.sendTo(ctx, opc, sls, rlc); err != nil {
+				logf("failed to send RLC to %s: %s", opc, err)
+			}
+			return
+		}
+		logf("discarded %s from %s: unknown local reference %d", m.MessageTypeName(), opc, dlr.Uint32())
+		return
+	}
+
+	c.handle(ctx, m, opc)
+}
+
+// handleCR handles the CR, which is delivered to the ConnectHandler of the local subsystem.
+func (n *Node) handleCR(ctx context.Context, cr *CR, opc params.PointCode, sls uint8) {
+	refuse := func(cause params.RefusalCauseValue) {
+		cref := NewCREF(cr.SourceLocalReference.Uint32(), cause)
+		if err := n.sendTo(ctx, opc, sls, cref); err != nil {
+			logf("failed to send CREF to %s: %s", opc, err)
+		}
+	}
+
+	cdpa, dpc, _, err := n.route(cr.CalledPartyAddress, sls)
+	if err != nil {
+		refuse(refusalCause(err))
+		return
+	}
+	if dpc != nil {
+		logf("refused CR from %s: relaying connection-oriented messages is not supported", opc)
+		refuse(params.RefusalCauseUnqualified)
+		return
+	}
+
+	n.mu.RLock()
+	h := n.connHandlers[cdpa.SubsystemNumber]
+	n.mu.RUnlock()
+	if h == nil || !cdpa.HasSSN() {
+		refuse(params.RefusalCauseUnequippedUser)
+		return
+	}
+
+	pcls := cr.ProtocolClass.Class()
+	if pcls < 2 {
+		logf("refused CR from %s: protocol class %d is not connection-oriented", opc, pcls)
+		refuse(params.RefusalCauseUnqualified)
+		return
+	}
+	if pcls > 3 {
+		// the protocol class is lowered to the one supported.
+		pcls = 3
+	}
+
+	// the window size proposed by the peer can be lowered, but not raised.
+	credit := n.Credit
+	if cr.Credit != nil && cr.Credit.Value() < credit {
+		credit = cr.Credit.Value()
+	}
+
+	c := n.newConnection(pcls, connStateIncoming, credit)
+	c.remoteRef = cr.SourceLocalReference.Uint32()
+	c.remotePC = opc
+	c.cdpa, c.cgpa = cdpa, cr.CallingPartyAddress
+	if err := n.allocate(c); err != nil {
+		logf("refused CR from %s: %s", opc, err)
+		refuse(params.RefusalCauseSCCPFailure)
+		return
+	}
+
+	ind := &ConnectIndication{
+		OPC:                 opc,
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cr.CallingPartyAddress,
+		conn:                c,
+	}
+	if cr.Data != nil {
+		ind.Data = cr.Data.Value()
+	}
+
+	(*h)(ind)
+}
+
+// sendTo sends the message to the DPC via MTP.
+func (n *Node) sendTo(ctx context.Context, dpc params.PointCode, sls uint8, m Message) error {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if err := n.mtp.Send(ctx, &mtp.Transfer{
+		OPC:  n.PointCode,
+		DPC:  dpc,
+		SI:   mtp.ServiceIndicatorSCCP,
+		NI:   n.NetworkIndicator,
+		SLS:  sls,
+		Data: b,
+	}); err != nil {
+		return fmt.Errorf("failed to send %s to %s: %w", m.MessageTypeName(), dpc, err)
+	}
+
+	return nil
+}
+
+// refusalCause returns the Refusal Cause corresponding to the routing failure.
+func refusalCause(err error) params.RefusalCauseValue {
+	rerr, ok := err.(*RoutingError)
+	if !ok {
+		return params.RefusalCauseUnqualified
+	}
+
+	switch rerr.Cause {
+	case params.ReturnCauseNoTranslationForAnAddressOfSuchNature:
+		return params.RefusalCauseNoTranslationForAnAddressOfSuchNature
+	case params.ReturnCauseNoTranslationForThisSpecificAddress:
+		return params.RefusalCauseDestinationAddressUnknown
+	case params.ReturnCauseSubsystemFailure:
+		return params.RefusalCauseSubsystemFailure
+	case params.ReturnCauseMTPFailure:
+		return params.RefusalCauseDestinationInaccessible
+	case params.ReturnCauseUnequippedUser:
+		return params.RefusalCauseUnequippedUser
+	case params.ReturnCauseHopCounterViolation:
+		return params.RefusalCauseHopCounterViolation
+	default:
+		return params.RefusalCauseUnqualified
+	}
+}
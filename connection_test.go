@@ -0,0 +1,515 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ssnAddr returns the party address routed on SSN with the point code.
+func ssnAddr(pc uint32, ssn uint8) *params.PartyAddress {
+	return params.NewCalledPartyAddress(
+		params.NewAddressIndicator(true, true, true, params.GTINoGT), pc, ssn, nil,
+	)
+}
+
+// connectedNodes returns the Nodes A (1) and B (2) connected with a Pipe.
+func connectedNodes(ctx context.Context, t *testing.T, timers sccp.Timers) (*sccp.Node, *sccp.Node) {
+	t.Helper()
+
+	sapA, sapB := mtp.NewPipe()
+	t.Cleanup(func() { _ = sapA.Close() })
+
+	a := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(1), sapA)
+	b := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(2), sapB)
+	a.Timers, b.Timers = timers, timers
+
+	go func() { _ = a.Serve(ctx) }()
+	go func() { _ = b.Serve(ctx) }()
+	return a, b
+}
+
+// peer emulates the Node 2 on the other end of the Pipe.
+type peer struct {
+	ctx context.Context
+	sap *mtp.Pipe
+}
+
+// emulatedPeer returns the Node 1 connected with the emulated peer.
+func emulatedPeer(ctx context.Context, t *testing.T) (*sccp.Node, *peer) {
+	t.Helper()
+
+	sap, p := mtp.NewPipe()
+	t.Cleanup(func() { _ = sap.Close() })
+
+	a := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(1), sap)
+	go func() { _ = a.Serve(ctx) }()
+	return a, &peer{ctx: ctx, sap: p}
+}
+
+// receive returns the message sent to the peer.
+func (p *peer) receive(t *testing.T) sccp.Message {
+	t.Helper()
+	prim, err := p.sap.Receive(p.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := sccp.ParseMessage(prim.(*mtp.Transfer).Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// send sends the message from the peer.
+func (p *peer) send(t *testing.T, m sccp.Message) {
+	t.Helper()
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.sap.Send(p.ctx, &mtp.Transfer{
+		OPC: params.VariantITU.NewPointCode(2), DPC: params.VariantITU.NewPointCode(1),
+		SI: mtp.ServiceIndicatorSCCP, Data: b,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a, b := connectedNodes(ctx, t, sccp.DefaultTimers)
+
+	accepted := make(chan *sccp.Connection, 1)
+	b.RegisterConnectHandler(8, func(ind *sccp.ConnectIndication) {
+		verify.Values(t, "CgPA SSN", ind.CallingPartyAddress.SubsystemNumber, uint8(6))
+		if string(ind.Data) == "refuse" {
+			if err := ind.Refuse(ctx, params.RefusalCauseEndUserCongestion); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+
+		c, err := ind.Accept(ctx, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		accepted <- c
+	})
+
+	t.Run("data transfer and release", func(t *testing.T) {
+		ca, err := a.Connect(ctx, 2, ssnAddr(2, 8), ssnAddr(1, 6), []byte{0x01})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cb *sccp.Connection
+		select {
+		case cb = <-accepted:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+
+		verify.Values(t, "references", []uint32{ca.RemoteReference(), cb.RemoteReference()},
+			[]uint32{cb.LocalReference(), ca.LocalReference()})
+
+		if err := ca.Send(ctx, []byte{0xde, 0xad}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := cb.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "A to B", got, []byte{0xde, 0xad})
+
+		if err := cb.Send(ctx, []byte{0xbe, 0xef}); err != nil {
+			t.Fatal(err)
+		}
+		got, err = ca.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "B to A", got, []byte{0xbe, 0xef})
+
+		if err := ca.Disconnect(ctx, params.ReleaseCauseEndUserOriginated); err != nil {
+			t.Fatal(err)
+		}
+		_, err = cb.Receive(ctx)
+		var rerr *sccp.ConnectionReleasedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionReleasedError, got %v", err)
+		}
+		verify.Values(t, "ReleaseCause", rerr.Cause, params.ReleaseCauseEndUserOriginated)
+
+		if err := ca.Send(ctx, []byte{0x00}); err == nil {
+			t.Error("expected error on released connection")
+		}
+	})
+
+	t.Run("refused by user", func(t *testing.T) {
+		_, err := a.Connect(ctx, 2, ssnAddr(2, 8), ssnAddr(1, 6), []byte("refuse"))
+		var rerr *sccp.ConnectionRefusedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionRefusedError, got %v", err)
+		}
+		verify.Values(t, "RefusalCause", rerr.Cause, params.RefusalCauseEndUserCongestion)
+	})
+
+	t.Run("unequipped user", func(t *testing.T) {
+		_, err := a.Connect(ctx, 2, ssnAddr(2, 9), ssnAddr(1, 6), []byte{0x01})
+		var rerr *sccp.ConnectionRefusedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionRefusedError, got %v", err)
+		}
+		verify.Values(t, "RefusalCause", rerr.Cause, params.RefusalCauseUnequippedUser)
+	})
+}
+
+func TestConnectionTimers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	timers := sccp.Timers{
+		ConnEst:   50 * time.Millisecond,
+		IAS:       time.Minute,
+		IAR:       100 * time.Millisecond,
+		Rel:       20 * time.Millisecond,
+		RepeatRel: 20 * time.Millisecond,
+		Int:       100 * time.Millisecond,
+		Freeze:    time.Minute,
+	}
+
+	t.Run("connection establishment", func(t *testing.T) {
+		sap, _ := mtp.NewPipe()
+		defer sap.Close()
+
+		n := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(1), sap)
+		n.Timers = timers
+		go func() { _ = n.Serve(ctx) }()
+
+		_, err := n.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+		var rerr *sccp.ConnectionRefusedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionRefusedError, got %v", err)
+		}
+		verify.Values(t, "RefusalCause", rerr.Cause, params.RefusalCauseExpirationOfTheConnectionEstablishmentTimer)
+	})
+
+	t.Run("receive inactivity", func(t *testing.T) {
+		a, b := connectedNodes(ctx, t, timers)
+		b.RegisterConnectHandler(8, func(ind *sccp.ConnectIndication) {
+			if _, err := ind.Accept(ctx, nil); err != nil {
+				t.Error(err)
+			}
+		})
+
+		c, err := a.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// no IT is sent in time as T(ias) is longer than T(iar).
+		_, err = c.Receive(ctx)
+		var rerr *sccp.ConnectionReleasedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionReleasedError, got %v", err)
+		}
+		verify.Values(t, "ReleaseCause", rerr.Cause, params.ReleaseCauseExpirationOfReceiveInactivityTimer)
+	})
+
+}
+
+func TestConnectionProtocolClass(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	const peerRef = 0x123456
+
+	t.Run("raised in CC", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		errc := make(chan error, 1)
+		go func() {
+			_, err := a.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+			errc <- err
+		}()
+
+		cr := p.receive(t).(*sccp.CR)
+		p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 3, params.NewCreditOptional(1)))
+
+		rlsd := p.receive(t).(*sccp.RLSD)
+		verify.Values(t, "ReleaseCause", rlsd.ReleaseCause.Value(), params.ReleaseCauseInconsistentConnectionData)
+		p.send(t, sccp.NewRLC(cr.SourceLocalReference.Uint32(), peerRef))
+
+		var rerr *sccp.ConnectionReleasedError
+		if err := <-errc; !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionReleasedError, got %v", err)
+		}
+		verify.Values(t, "ReleaseCause", rerr.Cause, params.ReleaseCauseInconsistentConnectionData)
+	})
+
+	t.Run("lowered in CC", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		connected := make(chan *sccp.Connection, 1)
+		go func() {
+			c, err := a.Connect(ctx, 3, ssnAddr(2, 8), nil, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			connected <- c
+		}()
+
+		cr := p.receive(t).(*sccp.CR)
+		p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 2))
+		c := <-connected
+		if c == nil {
+			t.FailNow()
+		}
+		verify.Values(t, "ProtocolClass", c.ProtocolClass(), 2)
+
+		if err := c.Send(ctx, []byte{0x01}); err != nil {
+			t.Fatal(err)
+		}
+		dt1 := p.receive(t).(*sccp.DT1)
+		verify.Values(t, "Data", dt1.Data.Value(), []byte{0x01})
+	})
+
+	t.Run("reset in class 2", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		connected := make(chan *sccp.Connection, 1)
+		go func() {
+			c, err := a.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			connected <- c
+		}()
+
+		cr := p.receive(t).(*sccp.CR)
+		p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 2))
+		c := <-connected
+		if c == nil {
+			t.FailNow()
+		}
+
+		p.send(t, sccp.NewRSR(c.LocalReference(), peerRef, params.ResetCauseEndUserOriginated))
+		e := p.receive(t).(*sccp.ERR)
+		verify.Values(t, "ErrorCause", e.ErrorCause.Value(), params.ErrorCauseServiceClassMismatch)
+
+		_, err := c.Receive(ctx)
+		var rerr *sccp.ConnectionReleasedError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionReleasedError, got %v", err)
+		}
+		verify.Values(t, "ReleaseCause", rerr.Cause, params.ReleaseCauseRemoteProcedureError)
+	})
+
+	t.Run("connectionless in CR", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		a.RegisterConnectHandler(8, func(ind *sccp.ConnectIndication) {
+			t.Error("unexpected N-CONNECT indication")
+		})
+
+		p.send(t, sccp.NewCR(peerRef, 1, ssnAddr(1, 8)))
+		cref := p.receive(t).(*sccp.CREF)
+		verify.Values(t, "DestinationLocalReference", cref.DestinationLocalReference.Uint32(), uint32(peerRef))
+		verify.Values(t, "RefusalCause", cref.RefusalCause.Value(), params.RefusalCauseUnqualified)
+	})
+}
+
+func TestConnectionFlowControl(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	t.Run("window", func(t *testing.T) {
+		a, b := connectedNodes(ctx, t, sccp.DefaultTimers)
+		a.Credit = 2
+
+		accepted := make(chan *sccp.Connection, 1)
+		b.RegisterConnectHandler(8, func(ind *sccp.ConnectIndication) {
+			c, err := ind.Accept(ctx, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			accepted <- c
+		})
+
+		ca, err := a.Connect(ctx, 3, ssnAddr(2, 8), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "ProtocolClass", ca.ProtocolClass(), 3)
+		cb := <-accepted
+
+		// more than the window and the modulo of the sequence numbers.
+		const n = 300
+		go func() {
+			for i := 0; i < n; i++ {
+				if err := ca.Send(ctx, []byte{uint8(i)}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		for i := 0; i < n; i++ {
+			got, err := cb.Receive(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "data", got, []byte{uint8(i)})
+		}
+	})
+
+	t.Run("queue overflow in class 2", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		connected := make(chan *sccp.Connection, 1)
+		go func() {
+			c, err := a.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			connected <- c
+		}()
+
+		const peerRef = 0x123456
+		cr := p.receive(t).(*sccp.CR)
+		p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 2))
+		c := <-connected
+		if c == nil {
+			t.FailNow()
+		}
+
+		// the Node keeps serving while the user does not receive the data.
+		for i := 0; i <= 64; i++ {
+			p.send(t, sccp.NewDT1(c.LocalReference(), false, []byte{uint8(i)}))
+		}
+		rlsd := p.receive(t).(*sccp.RLSD)
+		verify.Values(t, "ReleaseCause", rlsd.ReleaseCause.Value(), params.ReleaseCauseEndUserCongestion)
+		p.send(t, sccp.NewRLC(c.LocalReference(), peerRef))
+
+		for i := 0; i < 64; i++ {
+			got, err := c.Receive(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "data", got, []byte{uint8(i)})
+		}
+		var rerr *sccp.ConnectionReleasedError
+		if _, err := c.Receive(ctx); !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionReleasedError, got %v", err)
+		}
+	})
+
+	t.Run("reassembly limit in class 2", func(t *testing.T) {
+		a, p := emulatedPeer(ctx, t)
+		connected := make(chan *sccp.Connection, 1)
+		go func() {
+			c, err := a.Connect(ctx, 2, ssnAddr(2, 8), nil, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			connected <- c
+		}()
+
+		const peerRef = 0x123456
+		cr := p.receive(t).(*sccp.CR)
+		p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 2))
+		c := <-connected
+		if c == nil {
+			t.FailNow()
+		}
+
+		// the M bit is kept set beyond the limit of 64k octets.
+		seg := make([]byte, 255)
+		for i := 0; i < 1<<16/len(seg)+1; i++ {
+			p.send(t, sccp.NewDT1(c.LocalReference(), true, seg))
+		}
+		rlsd := p.receive(t).(*sccp.RLSD)
+		verify.Values(t, "ReleaseCause", rlsd.ReleaseCause.Value(), params.ReleaseCauseRemoteProcedureError)
+	})
+
+	// the peer is emulated on the other end of the Pipe.
+	a, p := emulatedPeer(ctx, t)
+	a.Credit = 1
+
+	const peerRef = 0x123456
+	connected := make(chan *sccp.Connection, 1)
+	go func() {
+		c, err := a.Connect(ctx, 3, ssnAddr(2, 8), nil, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		connected <- c
+	}()
+	cr := p.receive(t).(*sccp.CR)
+	verify.Values(t, "CR Credit", cr.Credit.Value(), uint8(1))
+	p.send(t, sccp.NewCC(cr.SourceLocalReference.Uint32(), peerRef, 3, params.NewCreditOptional(1)))
+	c := <-connected
+	if c == nil {
+		t.FailNow()
+	}
+
+	t.Run("acknowledgement", func(t *testing.T) {
+		if err := c.Send(ctx, []byte{0x01}); err != nil {
+			t.Fatal(err)
+		}
+		dt2 := p.receive(t).(*sccp.DT2)
+		verify.Values(t, "P(S)", dt2.SequencingSegmenting.SendSequenceNumber, uint8(0))
+
+		// the window is closed until AK is received.
+		tctx, tcancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer tcancel()
+		if err := c.Send(tctx, []byte{0x02}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+
+		p.send(t, sccp.NewAK(c.LocalReference(), 1, 1))
+		if err := c.Send(ctx, []byte{0x03}); err != nil {
+			t.Fatal(err)
+		}
+		dt2 = p.receive(t).(*sccp.DT2)
+		verify.Values(t, "P(S)", dt2.SequencingSegmenting.SendSequenceNumber, uint8(1<<1))
+		verify.Values(t, "Data", dt2.Data.Value(), []byte{0x03})
+
+		// acknowledged when the user receives it as the window size is 1.
+		p.send(t, sccp.NewDT2(c.LocalReference(), 0, 2, false, []byte{0x04}))
+		got, err := c.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "received", got, []byte{0x04})
+		ak := p.receive(t).(*sccp.AK)
+		verify.Values(t, "AK P(R)", ak.ReceiveSequenceNumber.Value(), uint8(1<<1))
+	})
+
+	t.Run("reset on out of window", func(t *testing.T) {
+		p.send(t, sccp.NewDT2(c.LocalReference(), 0, 2, false, []byte{0x05}))
+		rsr := p.receive(t).(*sccp.RSR)
+		verify.Values(t, "ResetCause", rsr.ResetCause.Value(), params.ResetCauseRemoteProcedureErrorMessageOutOfWindow)
+
+		_, err := c.Receive(ctx)
+		var rerr *sccp.ConnectionResetError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ConnectionResetError, got %v", err)
+		}
+
+		p.send(t, sccp.NewRSC(c.LocalReference(), peerRef))
+		if err := c.Send(ctx, []byte{0x06}); err != nil {
+			t.Fatal(err)
+		}
+		dt2 := p.receive(t).(*sccp.DT2)
+		verify.Values(t, "P(S) after reset", dt2.SequencingSegmenting.SendSequenceNumber, uint8(0))
+	})
+}
@@ -0,0 +1,313 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// CR represents a SCCP Message Connection request (CR).
+type CR struct {
+	Type                    MsgType
+	SourceLocalReference    *params.LocalReference
+	ProtocolClass           *params.ProtocolClass
+	CalledPartyAddress      *params.PartyAddress
+	Credit                  *params.Credit
+	CallingPartyAddress     *params.PartyAddress
+	Data                    *params.Data
+	HopCounter              *params.HopCounter
+	Importance              *params.Importance
+	EndOfOptionalParameters *params.EndOfOptionalParameters
+
+	ptr1, ptr2 uint8
+}
+
+// NewCR creates a new CR.
+//
+// The optional parameters given as opts should be the ones created as optional,
+// e.g., params.NewCallingPartyAddressOptional, params.NewDataOptional.
+func NewCR(slr uint32, pcls int, cdpa *params.PartyAddress, opts ...params.Parameter) *CR {
+	c := &CR{
+		Type:                 MsgTypeCR,
+		SourceLocalReference: params.NewSourceLocalReference(slr),
+		ProtocolClass:        params.NewProtocolClass(pcls, false),
+		CalledPartyAddress:   cdpa,
+	}
+
+	c.ptr1 = 2
+	c.ptr2 = 0
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCallingPartyAddress:
+			c.CallingPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeHopCounter:
+			c.HopCounter = opt.(*params.HopCounter)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			c.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			logf("unexpected parameter: %s in NewCR", opt.Code())
+		}
+	}
+
+	if len(opts) > 0 {
+		c.ptr2 = c.ptr1 + uint8(cdpa.MarshalLen()) - 1
+		// so that users don't have to give EndOfOptionalParameters explicitly
+		c.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
+	}
+
+	return c
+}
+
+// MarshalBinary returns the byte sequence generated from a CR instance.
+func (c *CR) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (c *CR) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(c.Type)
+
+	n := 1
+	m, err := c.SourceLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = c.ProtocolClass.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = c.ptr1
+	b[n+1] = c.ptr2
+
+	cdpaStart := n + int(c.ptr1)
+	if l < cdpaStart {
+		return io.ErrUnexpectedEOF
+	}
+	if _, err := c.CalledPartyAddress.Write(b[cdpaStart:]); err != nil {
+		return err
+	}
+
+	if c.ptr2 == 0 {
+		return nil
+	}
+
+	offset := n + 1 + int(c.ptr2)
+	if l < offset {
+		return io.ErrUnexpectedEOF
+	}
+	for _, param := range c.optionalParameters() {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+
+	return nil
+}
+
+// ParseCR decodes given byte sequence as a SCCP CR.
+func ParseCR(b []byte) (*CR, error) {
+	return ParseCRWithVariant(params.VariantITU, b)
+}
+
+// ParseCRWithVariant decodes given byte sequence as a SCCP CR in the given protocol variant.
+func ParseCRWithVariant(v params.Variant, b []byte) (*CR, error) {
+	c := &CR{}
+	if err := c.unmarshalBinaryWithVariant(v, b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP CR.
+func (c *CR) UnmarshalBinary(b []byte) error {
+	return c.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP CR,
+// decoding the parameters in the given protocol variant.
+func (c *CR) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
+	l := len(b)
+	if l < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	c.SourceLocalReference, n, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	c.ProtocolClass, n, err = params.ParseProtocolClass(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	c.ptr1 = b[offset]
+	offsetPtr1 := offset + int(c.ptr1)
+	if l < offsetPtr1+1 { // where CdPA starts
+		return io.ErrUnexpectedEOF
+	}
+	c.ptr2 = b[offset+1]
+	offsetPtr2 := offset + 1 + int(c.ptr2)
+	if c.ptr2 != 0 && l < offsetPtr2+1 { // where optional parameters start
+		return io.ErrUnexpectedEOF
+	}
+
+	cdpaEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if l < cdpaEnd {                               // where CdPA ends
+		return io.ErrUnexpectedEOF
+	}
+
+	c.CalledPartyAddress, _, err = params.ParseCalledPartyAddressWithVariant(v, b[offsetPtr1:cdpaEnd])
+	if err != nil {
+		return err
+	}
+
+	if c.ptr2 == 0 {
+		return nil
+	}
+
+	opts, _, err := params.ParseOptionalParametersWithVariant(v, b[offsetPtr2:])
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCallingPartyAddress:
+			c.CallingPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeHopCounter:
+			c.HopCounter = opt.(*params.HopCounter)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			c.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		}
+	}
+
+	return nil
+}
+
+// optionalParameters returns the optional parameters that are present, in the order to be serialized.
+func (c *CR) optionalParameters() []params.Parameter {
+	var opts []params.Parameter
+	if param := c.Credit; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.CallingPartyAddress; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.Data; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.HopCounter; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.Importance; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.EndOfOptionalParameters; param != nil {
+		opts = append(opts, param)
+	}
+
+	return opts
+}
+
+// MarshalLen returns the serial length.
+func (c *CR) MarshalLen() int {
+	l := 7 // MsgType + SourceLocalReference + ProtocolClass + Pointers
+
+	if param := c.CalledPartyAddress; param != nil {
+		l += param.MarshalLen()
+	}
+
+	// if optional parameters exist
+	if c.ptr2 != 0 {
+		for _, param := range c.optionalParameters() {
+			l += param.MarshalLen()
+		}
+	}
+
+	return l
+}
+
+// String returns the CR values in human readable format.
+func (c *CR) String() string {
+	return fmt.Sprintf("%s: {SourceLocalReference: %s, ProtocolClass: %s, CalledPartyAddress: %v, Credit: %v, CallingPartyAddress: %v, Data: %v, HopCounter: %v, Importance: %v}",
+		c.Type,
+		c.SourceLocalReference,
+		c.ProtocolClass,
+		c.CalledPartyAddress,
+		c.Credit,
+		c.CallingPartyAddress,
+		c.Data,
+		c.HopCounter,
+		c.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (c *CR) MessageType() MsgType {
+	return MsgTypeCR
+}
+
+// MessageTypeName returns the Message Type in string.
+func (c *CR) MessageTypeName() string {
+	return c.MessageType().String()
+}
+
+// CdGT returns the GT in CalledPartyAddress in human readable string.
+func (c *CR) CdGT() string {
+	if c.CalledPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return c.CalledPartyAddress.Address()
+}
+
+// CgGT returns the GT in CallingPartyAddress in human readable string.
+func (c *CR) CgGT() string {
+	if c.CallingPartyAddress == nil || c.CallingPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return c.CallingPartyAddress.Address()
+}
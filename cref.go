@@ -0,0 +1,246 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// CREF represents a SCCP Message Connection refused (CREF).
+type CREF struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	RefusalCause              *params.RefusalCause
+	CalledPartyAddress        *params.PartyAddress
+	Data                      *params.Data
+	Importance                *params.Importance
+	EndOfOptionalParameters   *params.EndOfOptionalParameters
+
+	ptr1 uint8
+}
+
+// NewCREF creates a new CREF.
+//
+// The optional parameters given as opts should be the ones created as optional,
+// e.g., params.NewCalledPartyAddressOptional, params.NewDataOptional.
+func NewCREF(dlr uint32, cause params.RefusalCauseValue, opts ...params.Parameter) *CREF {
+	c := &CREF{
+		Type:                      MsgTypeCREF,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		RefusalCause:              params.NewCause(cause),
+	}
+
+	c.ptr1 = 0
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			c.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			logf("unexpected parameter: %s in NewCREF", opt.Code())
+		}
+	}
+
+	if len(opts) > 0 {
+		c.ptr1 = 1
+		// so that users don't have to give EndOfOptionalParameters explicitly
+		c.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
+	}
+
+	return c
+}
+
+// MarshalBinary returns the byte sequence generated from a CREF instance.
+func (c *CREF) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (c *CREF) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 6 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(c.Type)
+
+	n := 1
+	m, err := c.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = c.RefusalCause.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = c.ptr1
+	if c.ptr1 == 0 {
+		return nil
+	}
+
+	offset := n + int(c.ptr1)
+	if l < offset {
+		return io.ErrUnexpectedEOF
+	}
+	for _, param := range c.optionalParameters() {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+
+	return nil
+}
+
+// ParseCREF decodes given byte sequence as a SCCP CREF.
+func ParseCREF(b []byte) (*CREF, error) {
+	return ParseCREFWithVariant(params.VariantITU, b)
+}
+
+// ParseCREFWithVariant decodes given byte sequence as a SCCP CREF in the given protocol variant.
+func ParseCREFWithVariant(v params.Variant, b []byte) (*CREF, error) {
+	c := &CREF{}
+	if err := c.unmarshalBinaryWithVariant(v, b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP CREF.
+func (c *CREF) UnmarshalBinary(b []byte) error {
+	return c.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP CREF,
+// decoding the parameters in the given protocol variant.
+func (c *CREF) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
+	l := len(b)
+	if l < 6 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	c.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	c.RefusalCause, n, err = params.ParseRefusalCause(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	c.ptr1 = b[offset]
+	if c.ptr1 == 0 {
+		return nil
+	}
+
+	offsetPtr1 := offset + int(c.ptr1)
+	if l < offsetPtr1+1 { // where optional parameters start
+		return io.ErrUnexpectedEOF
+	}
+
+	opts, _, err := params.ParseOptionalParametersWithVariant(v, b[offsetPtr1:])
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			c.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		}
+	}
+
+	return nil
+}
+
+// optionalParameters returns the optional parameters that are present, in the order to be serialized.
+func (c *CREF) optionalParameters() []params.Parameter {
+	var opts []params.Parameter
+	if param := c.CalledPartyAddress; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.Data; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.Importance; param != nil {
+		opts = append(opts, param)
+	}
+	if param := c.EndOfOptionalParameters; param != nil {
+		opts = append(opts, param)
+	}
+
+	return opts
+}
+
+// MarshalLen returns the serial length.
+func (c *CREF) MarshalLen() int {
+	l := 6 // MsgType + DestinationLocalReference + RefusalCause + Pointer
+
+	// if optional parameters exist
+	if c.ptr1 != 0 {
+		for _, param := range c.optionalParameters() {
+			l += param.MarshalLen()
+		}
+	}
+
+	return l
+}
+
+// String returns the CREF values in human readable format.
+func (c *CREF) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, RefusalCause: %s, CalledPartyAddress: %v, Data: %v, Importance: %v}",
+		c.Type,
+		c.DestinationLocalReference,
+		c.RefusalCause,
+		c.CalledPartyAddress,
+		c.Data,
+		c.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (c *CREF) MessageType() MsgType {
+	return MsgTypeCREF
+}
+
+// MessageTypeName returns the Message Type in string.
+func (c *CREF) MessageTypeName() string {
+	return c.MessageType().String()
+}
@@ -0,0 +1,163 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DT1 represents a SCCP Message Data form 1 (DT1).
+type DT1 struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SegmentingReassembling    *params.SegmentingReassembling
+	Data                      *params.Data
+
+	ptr1 uint8
+}
+
+// NewDT1 creates a new DT1.
+func NewDT1(dlr uint32, moreData bool, data []byte) *DT1 {
+	return &DT1{
+		Type:                      MsgTypeDT1,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SegmentingReassembling:    params.NewSegmentingReassembling(moreData),
+		Data:                      params.NewData(data),
+		ptr1:                      1,
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a DT1 instance.
+func (d *DT1) MarshalBinary() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (d *DT1) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 6 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(d.Type)
+
+	n := 1
+	m, err := d.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = d.SegmentingReassembling.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = d.ptr1
+	dataStart := n + int(d.ptr1)
+	if l < dataStart {
+		return io.ErrUnexpectedEOF
+	}
+	if _, err := d.Data.Write(b[dataStart:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseDT1 decodes given byte sequence as a SCCP DT1.
+func ParseDT1(b []byte) (*DT1, error) {
+	d := &DT1{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP DT1.
+func (d *DT1) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < 6 {
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	d.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	d.SegmentingReassembling, n, err = params.ParseSegmentingReassembling(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	d.ptr1 = b[offset]
+	offsetPtr1 := offset + int(d.ptr1)
+	if l < offsetPtr1+1 { // where Data starts
+		return io.ErrUnexpectedEOF
+	}
+
+	dataEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if l < dataEnd {                               // where Data ends
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Data, _, err = params.ParseData(b[offsetPtr1:dataEnd])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (d *DT1) MarshalLen() int {
+	l := 6 // MsgType + DestinationLocalReference + SegmentingReassembling + Pointer
+
+	l += int(d.ptr1) - 1 // length between the pointer and Data
+	if param := d.Data; param != nil {
+		l += param.MarshalLen()
+	}
+
+	return l
+}
+
+// String returns the DT1 values in human readable format.
+func (d *DT1) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SegmentingReassembling: %s, Data: %s}",
+		d.Type,
+		d.DestinationLocalReference,
+		d.SegmentingReassembling,
+		d.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (d *DT1) MessageType() MsgType {
+	return MsgTypeDT1
+}
+
+// MessageTypeName returns the Message Type in string.
+func (d *DT1) MessageTypeName() string {
+	return d.MessageType().String()
+}
@@ -0,0 +1,164 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DT2 represents a SCCP Message Data form 2 (DT2).
+type DT2 struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SequencingSegmenting      *params.SequencingSegmenting
+	Data                      *params.Data
+
+	ptr1 uint8
+}
+
+// NewDT2 creates a new DT2.
+// P(S) and P(R) are given in 0-127, and shifted to the left by 1 in SequencingSegmenting.
+func NewDT2(dlr uint32, ps, pr uint8, moreData bool, data []byte) *DT2 {
+	return &DT2{
+		Type:                      MsgTypeDT2,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SequencingSegmenting:      params.NewSequencingSegmentingFromSequence(ps, pr, moreData),
+		Data:                      params.NewData(data),
+		ptr1:                      1,
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a DT2 instance.
+func (d *DT2) MarshalBinary() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (d *DT2) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(d.Type)
+
+	n := 1
+	m, err := d.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = d.SequencingSegmenting.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = d.ptr1
+	dataStart := n + int(d.ptr1)
+	if l < dataStart {
+		return io.ErrUnexpectedEOF
+	}
+	if _, err := d.Data.Write(b[dataStart:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseDT2 decodes given byte sequence as a SCCP DT2.
+func ParseDT2(b []byte) (*DT2, error) {
+	d := &DT2{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP DT2.
+func (d *DT2) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	d.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	d.SequencingSegmenting, n, err = params.ParseSequencingSegmenting(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	d.ptr1 = b[offset]
+	offsetPtr1 := offset + int(d.ptr1)
+	if l < offsetPtr1+1 { // where Data starts
+		return io.ErrUnexpectedEOF
+	}
+
+	dataEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if l < dataEnd {                               // where Data ends
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Data, _, err = params.ParseData(b[offsetPtr1:dataEnd])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (d *DT2) MarshalLen() int {
+	l := 7 // MsgType + DestinationLocalReference + SequencingSegmenting + Pointer
+
+	l += int(d.ptr1) - 1 // length between the pointer and Data
+	if param := d.Data; param != nil {
+		l += param.MarshalLen()
+	}
+
+	return l
+}
+
+// String returns the DT2 values in human readable format.
+func (d *DT2) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SequencingSegmenting: %s, Data: %s}",
+		d.Type,
+		d.DestinationLocalReference,
+		d.SequencingSegmenting,
+		d.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (d *DT2) MessageType() MsgType {
+	return MsgTypeDT2
+}
+
+// MessageTypeName returns the Message Type in string.
+func (d *DT2) MessageTypeName() string {
+	return d.MessageType().String()
+}
@@ -0,0 +1,101 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// EA represents a SCCP Message Expedited data acknowledgement (EA).
+type EA struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+}
+
+// NewEA creates a new EA.
+func NewEA(dlr uint32) *EA {
+	return &EA{
+		Type:                      MsgTypeEA,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an EA instance.
+func (e *EA) MarshalBinary() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (e *EA) MarshalTo(b []byte) error {
+	if len(b) < e.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(e.Type)
+
+	if _, err := e.DestinationLocalReference.Write(b[1:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseEA decodes given byte sequence as a SCCP EA.
+func ParseEA(b []byte) (*EA, error) {
+	e := &EA{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP EA.
+func (e *EA) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Type = MsgType(b[0])
+
+	var err error
+	e.DestinationLocalReference, _, err = params.ParseDestinationLocalReference(b[1:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (e *EA) MarshalLen() int {
+	return 4 // MsgType + DestinationLocalReference
+}
+
+// String returns the EA values in human readable format.
+func (e *EA) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s}",
+		e.Type,
+		e.DestinationLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (e *EA) MessageType() MsgType {
+	return MsgTypeEA
+}
+
+// MessageTypeName returns the Message Type in string.
+func (e *EA) MessageTypeName() string {
+	return e.MessageType().String()
+}
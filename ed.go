@@ -0,0 +1,152 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ED represents a SCCP Message Expedited data (ED).
+type ED struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	Data                      *params.Data
+
+	ptr1 uint8
+}
+
+// NewED creates a new ED.
+func NewED(dlr uint32, data []byte) *ED {
+	if len(data) > 32 {
+		logf("too long data in NewED: %d octets, must be <= 32", len(data))
+	}
+
+	return &ED{
+		Type:                      MsgTypeED,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		Data:                      params.NewData(data),
+		ptr1:                      1,
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an ED instance.
+func (e *ED) MarshalBinary() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (e *ED) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(e.Type)
+
+	n := 1
+	m, err := e.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = e.ptr1
+	dataStart := n + int(e.ptr1)
+	if l < dataStart {
+		return io.ErrUnexpectedEOF
+	}
+	if _, err := e.Data.Write(b[dataStart:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseED decodes given byte sequence as a SCCP ED.
+func ParseED(b []byte) (*ED, error) {
+	e := &ED{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP ED.
+func (e *ED) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	e.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	e.ptr1 = b[offset]
+	offsetPtr1 := offset + int(e.ptr1)
+	if l < offsetPtr1+1 { // where Data starts
+		return io.ErrUnexpectedEOF
+	}
+
+	dataEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if l < dataEnd {                               // where Data ends
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Data, _, err = params.ParseData(b[offsetPtr1:dataEnd])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (e *ED) MarshalLen() int {
+	l := 5 // MsgType + DestinationLocalReference + Pointer
+
+	l += int(e.ptr1) - 1 // length between the pointer and Data
+	if param := e.Data; param != nil {
+		l += param.MarshalLen()
+	}
+
+	return l
+}
+
+// String returns the ED values in human readable format.
+func (e *ED) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, Data: %s}",
+		e.Type,
+		e.DestinationLocalReference,
+		e.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (e *ED) MessageType() MsgType {
+	return MsgTypeED
+}
+
+// MessageTypeName returns the Message Type in string.
+func (e *ED) MessageTypeName() string {
+	return e.MessageType().String()
+}
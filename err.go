@@ -0,0 +1,119 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ERR represents a SCCP Message Protocol data unit error (ERR).
+type ERR struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	ErrorCause                *params.ErrorCause
+}
+
+// NewERR creates a new ERR.
+func NewERR(dlr uint32, cause params.ErrorCauseValue) *ERR {
+	return &ERR{
+		Type:                      MsgTypeERR,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		ErrorCause:                params.NewCause(cause),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an ERR instance.
+func (e *ERR) MarshalBinary() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (e *ERR) MarshalTo(b []byte) error {
+	if len(b) < e.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(e.Type)
+
+	n := 1
+	m, err := e.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := e.ErrorCause.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseERR decodes given byte sequence as a SCCP ERR.
+func ParseERR(b []byte) (*ERR, error) {
+	e := &ERR{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP ERR.
+func (e *ERR) UnmarshalBinary(b []byte) error {
+	if len(b) < 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	e.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	e.ErrorCause, _, err = params.ParseErrorCause(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (e *ERR) MarshalLen() int {
+	return 5 // MsgType + DestinationLocalReference + ErrorCause
+}
+
+// String returns the ERR values in human readable format.
+func (e *ERR) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, ErrorCause: %s}",
+		e.Type,
+		e.DestinationLocalReference,
+		e.ErrorCause,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (e *ERR) MessageType() MsgType {
+	return MsgTypeERR
+}
+
+// MessageTypeName returns the Message Type in string.
+func (e *ERR) MessageTypeName() string {
+	return e.MessageType().String()
+}
@@ -5,7 +5,10 @@
 package sccp
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
 )
 
 // UnsupportedTypeError indicates the value in Version field is invalid.
@@ -15,3 +18,52 @@ type UnsupportedTypeError uint8
 func (e UnsupportedTypeError) Error() string {
 	return fmt.Sprintf("sccp: got unsupported type %d", e)
 }
+
+// RoutingError indicates the SCCP Routing Control failed to route the message,
+// with the Return Cause to be notified to the originator.
+type RoutingError struct {
+	Cause params.ReturnCauseValue
+}
+
+// Error returns the type of receiver and the cause of the failure.
+func (e *RoutingError) Error() string {
+	return fmt.Sprintf("sccp: failed to route: %s", e.Cause)
+}
+
+// ErrTooLongData indicates the data in LUDT or LUDTS exceed MaxLongDataLen.
+var ErrTooLongData = fmt.Errorf("sccp: too long data, must be <= %d octets", MaxLongDataLen)
+
+// ErrNoLocalReference indicates no local reference is available for a new connection.
+var ErrNoLocalReference = errors.New("sccp: no local reference available")
+
+// ConnectionRefusedError indicates the connection is refused by the peer or SCCP,
+// with the Refusal Cause.
+type ConnectionRefusedError struct {
+	Cause params.RefusalCauseValue
+}
+
+// Error returns the type of receiver and the cause of the refusal.
+func (e *ConnectionRefusedError) Error() string {
+	return fmt.Sprintf("sccp: connection refused: %s", e.Cause)
+}
+
+// ConnectionReleasedError indicates the connection is released, with the Release Cause.
+type ConnectionReleasedError struct {
+	Cause params.ReleaseCauseValue
+}
+
+// Error returns the type of receiver and the cause of the release.
+func (e *ConnectionReleasedError) Error() string {
+	return fmt.Sprintf("sccp: connection released: %s", e.Cause)
+}
+
+// ConnectionResetError indicates the connection is reset, with the Reset Cause.
+// The connection can still be used after the reset.
+type ConnectionResetError struct {
+	Cause params.ResetCauseValue
+}
+
+// Error returns the type of receiver and the cause of the reset.
+func (e *ConnectionResetError) Error() string {
+	return fmt.Sprintf("sccp: connection reset: %s", e.Cause)
+}
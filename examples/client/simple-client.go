@@ -19,6 +19,7 @@ import (
 	m3params "github.com/wmnsk/go-m3ua/messages/params"
 
 	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/mtp"
 	"github.com/wmnsk/go-sccp/params"
 	"github.com/wmnsk/go-sccp/utils"
 
@@ -72,6 +73,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// use M3UA as the MTP to send SCCP messages with the routing label.
+	sap := mtp.NewM3UA(params.VariantITU, m3conn)
+	transfer := func(b []byte) error {
+		return sap.Send(ctx, &mtp.Transfer{
+			OPC:  params.VariantITU.NewPointCode(m3config.OriginatingPointCode),
+			DPC:  params.VariantITU.NewPointCode(m3config.DestinationPointCode),
+			SI:   mtp.ServiceIndicatorSCCP,
+			SLS:  m3config.SignalingLinkSelection,
+			Data: b,
+		})
+	}
+
 	gti := params.GTITTNPESNAI
 	ai := params.NewAddressIndicator(false, true, false, gti)
 	cdPA := params.NewCalledPartyAddress(
@@ -125,7 +138,7 @@ func main() {
 	// send once
 	i := 1
 	log.Printf("Sending %04d: %v", i, udt)
-	if _, err := m3conn.Write(u); err != nil {
+	if err := transfer(u); err != nil {
 		log.Fatal(err)
 	}
 
@@ -148,7 +161,7 @@ func main() {
 			}
 
 			log.Printf("Sending %04d: %v", i, msg)
-			if _, err := m3conn.Write(b); err != nil {
+			if err := transfer(b); err != nil {
 				log.Fatal(err)
 			}
 		}
@@ -16,19 +16,22 @@ import (
 	"net"
 	"time"
 
-	"github.com/wmnsk/go-m3ua/messages/params"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
 	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
 
 	"github.com/ishidawataru/sctp"
 	"github.com/wmnsk/go-m3ua"
 )
 
-func serve(conn *m3ua.Conn) {
-	defer conn.Close()
+func serve(ctx context.Context, conn *m3ua.Conn) {
+	// use M3UA as the MTP to see the routing label of the SCCP messages.
+	sap := mtp.NewM3UA(params.VariantITU, conn)
+	defer sap.Close()
 
-	buf := make([]byte, 1500)
 	for {
-		n, err := conn.Read(buf)
+		prim, err := sap.Receive(ctx)
 		if err != nil {
 			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
 				log.Printf("Closed M3UA conn with: %s, waiting to come back on", conn.RemoteAddr())
@@ -38,16 +41,20 @@ func serve(conn *m3ua.Conn) {
 			return
 		}
 
-		b := make([]byte, n)
-		copy(b, buf[:n])
+		tr, ok := prim.(*mtp.Transfer)
+		if !ok {
+			log.Printf("Received %s", prim)
+			continue
+		}
+
 		go func() {
-			msg, err := sccp.ParseMessage(b)
+			msg, err := sccp.ParseMessage(tr.Data)
 			if err != nil {
-				log.Printf("Failed to parse SCCP message: %s, %x", err, b)
+				log.Printf("Failed to parse SCCP message: %s, %x", err, tr.Data)
 				return
 			}
 
-			log.Printf("Received SCCP message: %v", msg)
+			log.Printf("Received SCCP message from %s: %v", tr.OPC, msg)
 		}()
 	}
 }
@@ -66,17 +73,17 @@ func main() {
 			Interval: 0,
 			Timer:    time.Duration(5 * time.Second),
 		},
-		0x22222222,                  // OriginatingPointCode
-		0x11111111,                  // DestinationPointCode
-		1,                           // AspIdentifier
-		params.TrafficModeLoadshare, // TrafficModeType
-		0,                           // NetworkAppearance
-		0,                           // CorrelationID
-		[]uint32{1, 2},              // RoutingContexts
-		params.ServiceIndSCCP,       // ServiceIndicator
-		0,                           // NetworkIndicator
-		0,                           // MessagePriority
-		1,                           // SignalingLinkSelection
+		0x22222222,                    // OriginatingPointCode
+		0x11111111,                    // DestinationPointCode
+		1,                             // AspIdentifier
+		m3params.TrafficModeLoadshare, // TrafficModeType
+		0,                             // NetworkAppearance
+		0,                             // CorrelationID
+		[]uint32{1, 2},                // RoutingContexts
+		m3params.ServiceIndSCCP,       // ServiceIndicator
+		0,                             // NetworkIndicator
+		0,                             // MessagePriority
+		1,                             // SignalingLinkSelection
 	)
 	config.AspIdentifier = nil
 	config.CorrelationID = nil
@@ -103,6 +110,6 @@ func main() {
 		}
 		log.Printf("Connected with: %s", conn.RemoteAddr())
 
-		go serve(conn)
+		go serve(ctx, conn)
 	}
 }
@@ -0,0 +1,262 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// seqModulo is the modulo of the sequence numbers P(S) and P(R).
+const seqModulo = 128
+
+// defaultCredit is the window size proposed in the class 3 connections by default.
+const defaultCredit = 8
+
+// sequence is the state of the flow control in protocol class 3. See Q.714 3.8.
+//
+// The sequence numbers are held in 0-127, not in the form in the octets.
+type sequence struct {
+	// sendCredit is the window size of the peer, i.e., the number of DT2 that can be
+	// sent without acknowledgement.
+	sendCredit uint8
+	// recvCredit is the window size of the local node.
+	recvCredit uint8
+
+	// vs is P(S) of the next DT2 to be sent.
+	vs uint8
+	// lowerSend is the lower edge of the sending window, i.e., P(R) last received.
+	lowerSend uint8
+	// vr is P(S) of the next DT2 expected to be received.
+	vr uint8
+	// lowerRecv is the lower edge of the receiving window, i.e., P(R) last sent.
+	lowerRecv uint8
+	// held is the number of the DT2 whose data are not received by the user yet,
+	// which are not acknowledged until received.
+	held uint8
+
+	// opened is closed when the sending window may be opened.
+	opened chan struct{}
+}
+
+func newSequence(credit uint8) *sequence {
+	return &sequence{
+		sendCredit: credit,
+		recvCredit: credit,
+		opened:     make(chan struct{}),
+	}
+}
+
+// seqDiff returns the distance from x to y in the sequence numbers.
+func seqDiff(x, y uint8) uint8 {
+	return (y - x) % seqModulo
+}
+
+// canSend reports whether the next DT2 is in the sending window.
+func (s *sequence) canSend() bool {
+	return seqDiff(s.lowerSend, s.vs) < s.sendCredit
+}
+
+// next returns P(S) and P(R) for the next DT2, and updates the state as it is sent.
+func (s *sequence) next() (ps, pr uint8) {
+	ps, pr = s.vs, s.acked()
+	s.vs = (s.vs + 1) % seqModulo
+	s.lowerRecv = pr
+	return ps, pr
+}
+
+// acked returns P(R) to be sent, which acknowledges the DT2 received except the
+// ones held for the user.
+func (s *sequence) acked() uint8 {
+	return (s.vr - s.held) % seqModulo
+}
+
+// acknowledge updates the lower edge of the sending window with P(R) received.
+// It fails if P(R) is not in the range from the last P(R) to the next P(S).
+func (s *sequence) acknowledge(pr uint8) bool {
+	if seqDiff(s.lowerSend, pr) > seqDiff(s.lowerSend, s.vs) {
+		return false
+	}
+
+	if pr != s.lowerSend {
+		s.lowerSend = pr
+		s.open()
+	}
+	return true
+}
+
+// receive checks P(S) and P(R) of the DT2 received, and updates the state.
+// If they are invalid, it returns false with the Reset Cause.
+func (s *sequence) receive(ps, pr uint8) (params.ResetCauseValue, bool) {
+	if seqDiff(s.lowerRecv, ps) >= s.recvCredit {
+		return params.ResetCauseRemoteProcedureErrorMessageOutOfWindow, false
+	}
+	if ps != s.vr {
+		return params.ResetCauseMessageOutOfOrderIncorrectSendSequenceNumber, false
+	}
+	if !s.acknowledge(pr) {
+		return params.ResetCauseMessageOutOfOrderIncorrectReceiveSequenceNumber, false
+	}
+
+	s.vr = (s.vr + 1) % seqModulo
+	return 0, true
+}
+
+// shouldAck reports whether the AK should be sent, which is when half of the
+// receiving window has been used without acknowledgement.
+func (s *sequence) shouldAck() bool {
+	return seqDiff(s.lowerRecv, s.acked()) >= (s.recvCredit+1)/2
+}
+
+// reset resets the sequence numbers to 0, which is done in the reset procedure.
+func (s *sequence) reset() {
+	s.vs, s.lowerSend, s.vr, s.lowerRecv, s.held = 0, 0, 0, 0, 0
+	s.open()
+}
+
+// open notifies the senders waiting for the sending window to be opened.
+func (s *sequence) open() {
+	close(s.opened)
+	s.opened = make(chan struct{})
+}
+
+// sendDT2 waits for the sending window to be opened and queues the data in DT2.
+// c.mu must be held by the caller, which is released while waiting.
+func (c *Connection) sendDT2(ctx context.Context, data []byte, more bool) (*outgoing, error) {
+	for c.state == connStateResetting || (c.state == connStateActive && !c.seq.canSend()) {
+		opened := c.seq.opened
+		c.mu.Unlock()
+		select {
+		case <-opened:
+		case <-c.released:
+		case <-ctx.Done():
+		}
+		c.mu.Lock()
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if c.state != connStateActive {
+		return nil, c.closedError()
+	}
+
+	ps, pr := c.seq.next()
+	return c.send(NewDT2(c.remoteRef, ps, pr, more, data)), nil
+}
+
+// handleDT2 handles the DT2 received, and delivers the data to the user.
+// c.mu must be held by the caller.
+func (c *Connection) handleDT2(m *DT2) {
+	ps := m.SequencingSegmenting.SendSequenceNumber >> 1
+	pr := m.SequencingSegmenting.ReceiveSequenceNumber >> 1
+	if cause, ok := c.seq.receive(ps, pr); !ok {
+		logf("DT2 with P(S)=%d, P(R)=%d for connection %d: %s", ps, pr, c.localRef, cause)
+		c.resetRequest(cause)
+		return
+	}
+
+	c.received()
+	data, ok := c.reassemble(m.Data.Value(), m.SequencingSegmenting.MoreData)
+	if !ok {
+		c.resetRequest(params.ResetCauseRemoteProcedureErrorGeneral)
+		return
+	}
+	if data != nil {
+		c.seq.held++
+		c.deliver(data)
+	}
+	if c.seq.shouldAck() {
+		c.sendAK()
+	}
+}
+
+// handleAK handles the AK received, which updates the sending window.
+// c.mu must be held by the caller.
+func (c *Connection) handleAK(m *AK) {
+	pr := m.ReceiveSequenceNumber.Value() >> 1
+	if !c.seq.acknowledge(pr) {
+		logf("AK with P(R)=%d for connection %d: out of range", pr, c.localRef)
+		c.resetRequest(params.ResetCauseMessageOutOfOrderIncorrectReceiveSequenceNumber)
+		return
+	}
+
+	c.received()
+	if credit := m.Credit.Value(); credit != c.seq.sendCredit {
+		c.seq.sendCredit = credit
+		c.seq.open()
+	}
+}
+
+// sendAK sends AK to acknowledge the DT2 received so far.
+// c.mu must be held by the caller.
+func (c *Connection) sendAK() {
+	c.seq.lowerRecv = c.seq.acked()
+	c.send(NewAK(c.remoteRef, c.seq.lowerRecv, c.seq.recvCredit))
+}
+
+// resetRequest starts the reset procedure by sending RSR, and notifies the user with
+// the cause. The Connection is released if RSC is not received before T(reset) expires.
+// c.mu must be held by the caller.
+func (c *Connection) resetRequest(cause params.ResetCauseValue) {
+	c.state = connStateResetting
+	c.indicateReset(cause)
+
+	c.send(NewRSR(c.remoteRef, c.localRef, cause))
+	c.startTimer(&c.tReset, c.node.Timers.Reset, func() {
+		c.disconnect(params.ReleaseCauseExpirationOfResetTimer)
+	})
+}
+
+// handleRSR handles the RSR received, which resets the sequence numbers and is
+// answered with RSC.
+// c.mu must be held by the caller.
+func (c *Connection) handleRSR(m *RSR) {
+	if c.state != connStateResetting {
+		c.indicateReset(m.ResetCause.Value())
+	}
+
+	c.stopTimer(&c.tReset)
+	c.seq.reset()
+	c.partial, c.data = nil, nil
+	c.state = connStateActive
+	c.received()
+
+	c.send(NewRSC(c.remoteRef, c.localRef))
+}
+
+// handleRSC handles the RSC received, which completes the reset procedure.
+// c.mu must be held by the caller.
+func (c *Connection) handleRSC() {
+	if c.state != connStateResetting {
+		return
+	}
+
+	c.stopTimer(&c.tReset)
+	c.seq.reset()
+	c.partial, c.data = nil, nil
+	c.state = connStateActive
+	c.received()
+}
+
+// serviceClassMismatch answers the message of the reset procedure received in protocol
+// class 2 with ERR, and releases the Connection as the peer does on receiving it.
+// c.mu must be held by the caller.
+func (c *Connection) serviceClassMismatch(m Message) {
+	logf("%s for connection %d: not in protocol class 3", m.MessageTypeName(), c.localRef)
+	c.send(NewERR(c.remoteRef, params.ErrorCauseServiceClassMismatch))
+	c.release(&ConnectionReleasedError{Cause: params.ReleaseCauseRemoteProcedureError})
+}
+
+// indicateReset notifies the user of the reset, i.e., N-RESET indication.
+// It is not queued if the user has not received the previous one yet.
+// c.mu must be held by the caller.
+func (c *Connection) indicateReset(cause params.ResetCauseValue) {
+	select {
+	case c.resets <- cause:
+	default:
+	}
+}
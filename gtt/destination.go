@@ -0,0 +1,208 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Mode is the mode of sharing the traffic among the Destinations of a Rule.
+// See Q.714 5.1.1 for more details.
+type Mode uint8
+
+// Mode values.
+const (
+	// ModeSolitary uses the only Destination of the Rule.
+	ModeSolitary Mode = iota
+	// ModeDominant uses the first available Destination in the order of the
+	// Destinations, i.e., the first one is the primary and the others are backups.
+	ModeDominant
+	// ModeLoadShared shares the traffic among the available Destinations
+	// by the Policy of the Rule.
+	ModeLoadShared
+)
+
+// String returns the Mode in a human-readable format.
+func (m Mode) String() string {
+	switch m {
+	case ModeSolitary:
+		return "solitary"
+	case ModeDominant:
+		return "dominant"
+	case ModeLoadShared:
+		return "load-shared"
+	default:
+		return fmt.Sprintf("Mode(%d)", m)
+	}
+}
+
+// Policy is the policy of choosing the Destination in ModeLoadShared.
+type Policy uint8
+
+// Policy values.
+const (
+	// PolicySLS chooses the Destination by the Signaling Link Selection, so that
+	// the messages with the same SLS are sent to the same Destination as long as
+	// it is available, which is required for the in-sequence delivery (class 1).
+	PolicySLS Policy = iota
+	// PolicyRoundRobin chooses the available Destinations in turn.
+	PolicyRoundRobin
+)
+
+// String returns the Policy in a human-readable format.
+func (p Policy) String() string {
+	switch p {
+	case PolicySLS:
+		return "SLS"
+	case PolicyRoundRobin:
+		return "round-robin"
+	default:
+		return fmt.Sprintf("Policy(%d)", p)
+	}
+}
+
+// Destination is a destination of the message as the result of a translation.
+type Destination struct {
+	// DPC is the Destination Point Code to which the message is routed.
+	// nil means that the DPC is not determined by the translation, e.g., the destination
+	// is the local node or the DPC is already included in the Called Party Address.
+	DPC *params.PointCode
+	// SSN is the new Subsystem Number set in the Called Party Address.
+	// 0 means that the Subsystem Number is not changed.
+	SSN uint8
+	// RouteOnSSN sets the Routing Indicator of the Called Party Address to
+	// route on SSN, which means that the translation is final.
+	RouteOnSSN bool
+	// CalledPartyAddress replaces the whole Called Party Address if not nil,
+	// and SSN and RouteOnSSN are ignored.
+	CalledPartyAddress *params.PartyAddress
+}
+
+// validate checks if the Destination is properly configured.
+func (d *Destination) validate() error {
+	if d.DPC != nil {
+		if err := d.DPC.Validate(); err != nil {
+			return fmt.Errorf("gtt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// subsystem returns the Subsystem Number of the destination, or 0 if it is
+// not determined by the Destination.
+func (d *Destination) subsystem() uint8 {
+	if d.CalledPartyAddress != nil {
+		return d.CalledPartyAddress.SubsystemNumber
+	}
+	return d.SSN
+}
+
+// subsystemKey is a key to hold the status of a subsystem at a signaling point.
+// The point code is held without the width, as in the comparison in the Node.
+type subsystemKey struct {
+	pc  uint32
+	ssn uint8
+}
+
+// SetPointCodeAvailable sets whether the signaling point is accessible or not,
+// typically on MTP-RESUME or MTP-PAUSE.
+//
+// The Destinations with the inaccessible DPC are skipped in the translation.
+func (t *Translator) SetPointCodeAvailable(pc params.PointCode, available bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if available {
+		delete(t.pausedPCs, pc.Value)
+		return
+	}
+	t.pausedPCs[pc.Value] = struct{}{}
+}
+
+// SetSubsystemAvailable sets whether the subsystem at the signaling point is available
+// or not, typically on receiving SSA or SSP.
+//
+// The Destinations with the prohibited subsystem are skipped in the translation.
+func (t *Translator) SetSubsystemAvailable(pc params.PointCode, ssn uint8, available bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := subsystemKey{pc.Value, ssn}
+	if available {
+		delete(t.prohibitedSSNs, key)
+		return
+	}
+	t.prohibitedSSNs[key] = struct{}{}
+}
+
+// unavailable returns the ReturnCause if the Destination is unavailable, or nil.
+// t.mu must be held by the caller.
+func (t *Translator) unavailable(d *Destination) *params.ReturnCauseValue {
+	if d.DPC == nil {
+		return nil
+	}
+
+	if _, ok := t.pausedPCs[d.DPC.Value]; ok {
+		c := params.ReturnCauseMTPFailure
+		return &c
+	}
+
+	if ssn := d.subsystem(); ssn != 0 {
+		if _, ok := t.prohibitedSSNs[subsystemKey{d.DPC.Value, ssn}]; ok {
+			c := params.ReturnCauseSubsystemFailure
+			return &c
+		}
+	}
+
+	return nil
+}
+
+// choose chooses the available Destination of the Rule.
+// t.mu must be held by the caller.
+func (t *Translator) choose(r *Rule, sls uint8) (*Destination, params.ReturnCauseValue, bool) {
+	if len(r.Destinations) == 0 {
+		return nil, params.ReturnCauseNoTranslationForThisSpecificAddress, false
+	}
+
+	// the cause of the first Destination is returned if none of them are available,
+	// as it is the primary one in ModeDominant.
+	cause := params.ReturnCauseUnqualified
+	available := make([]*Destination, 0, len(r.Destinations))
+	for i, d := range r.Destinations {
+		if c := t.unavailable(d); c != nil {
+			if i == 0 {
+				cause = *c
+			}
+			continue
+		}
+		available = append(available, d)
+	}
+
+	if len(available) == 0 {
+		return nil, cause, false
+	}
+
+	switch r.Mode {
+	case ModeLoadShared:
+		switch r.Policy {
+		case PolicyRoundRobin:
+			n := r.next.Add(1) - 1
+			return available[int(n%uint32(len(available)))], 0, true
+		default:
+			// keep the SLS mapped to the same Destination while it is available,
+			// and share the traffic among the others only while it is not.
+			d := r.Destinations[int(sls)%len(r.Destinations)]
+			if t.unavailable(d) == nil {
+				return d, 0, true
+			}
+			return available[int(sls)%len(available)], 0, true
+		}
+	default:
+		return available[0], 0, true
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtt provides the Global Title Translation of SCCP, which translates the
+// Global Title in the Called Party Address into the destination of the message.
+//
+// See Q.714 2.4 for the details of the translation function.
+package gtt
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Selector is a set of the Global Title fields that selects the translation table
+// to be looked up, i.e., the "type" of the Global Title.
+//
+// The fields not included in the Global Title (e.g., NAI in ANSI) are left 0.
+// ESBCDOdd and ESBCDEven are treated as the same encoding scheme, as the odd/even
+// is the property of each address rather than the type of the Global Title.
+type Selector struct {
+	TranslationType          params.TranslationType
+	NumberingPlan            params.NumberingPlan
+	NatureOfAddressIndicator params.NatureOfAddressIndicator
+	EncodingScheme           params.EncodingScheme
+}
+
+// NewSelector creates a new Selector.
+func NewSelector(tt params.TranslationType, np params.NumberingPlan, nai params.NatureOfAddressIndicator, es params.EncodingScheme) Selector {
+	return Selector{
+		TranslationType:          tt,
+		NumberingPlan:            np,
+		NatureOfAddressIndicator: nai,
+		EncodingScheme:           es,
+	}.normalize()
+}
+
+// SelectorOf returns the Selector of the given GlobalTitle.
+func SelectorOf(gt *params.GlobalTitle) Selector {
+	return NewSelector(gt.TranslationType, gt.NumberingPlan, gt.NatureOfAddressIndicator, gt.EncodingScheme)
+}
+
+// normalize removes the odd/even information from the Selector.
+func (s Selector) normalize() Selector {
+	s.NatureOfAddressIndicator = s.NatureOfAddressIndicator.Even()
+	if s.EncodingScheme == params.ESBCDOdd {
+		s.EncodingScheme = params.ESBCDEven
+	}
+	return s
+}
+
+// String returns the Selector in a human-readable format.
+func (s Selector) String() string {
+	return fmt.Sprintf("{TranslationType: %d, NumberingPlan: %s, NatureOfAddressIndicator: %s, EncodingScheme: %s}",
+		s.TranslationType, s.NumberingPlan, s.NatureOfAddressIndicator, s.EncodingScheme,
+	)
+}
+
+// Rule is a translation rule that is applied to the Global Titles of the Selector
+// whose address begins with Prefix.
+//
+// An empty Prefix matches any address, which can be used as the default rule.
+type Rule struct {
+	Selector Selector
+	Prefix   string
+
+	// Mode is the mode of sharing the traffic among the Destinations.
+	// ModeSolitary requires exactly one Destination.
+	Mode Mode
+	// Policy is the policy of choosing the Destination in ModeLoadShared.
+	Policy Policy
+	// Destinations are the candidate destinations of the translation, in the
+	// order of priority in ModeDominant.
+	Destinations []*Destination
+
+	// next is the counter for PolicyRoundRobin.
+	next atomic.Uint32
+}
+
+// validate checks if the Rule is properly configured.
+func (r *Rule) validate() error {
+	if err := validateDigits(r.Prefix); err != nil {
+		return err
+	}
+
+	switch r.Mode {
+	case ModeSolitary:
+		if len(r.Destinations) != 1 {
+			return fmt.Errorf("gtt: %s rule must have exactly one destination, got %d", r.Mode, len(r.Destinations))
+		}
+	case ModeDominant, ModeLoadShared:
+		if len(r.Destinations) == 0 {
+			return fmt.Errorf("gtt: %s rule must have at least one destination", r.Mode)
+		}
+	default:
+		return fmt.Errorf("gtt: invalid mode: %s", r.Mode)
+	}
+
+	for _, d := range r.Destinations {
+		if err := d.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Result is the result of a translation.
+type Result struct {
+	// DPC is the Destination Point Code to which the message is routed, or nil if
+	// it is not determined by the translation.
+	DPC *params.PointCode
+	// CalledPartyAddress is the translated Called Party Address, which is a copy
+	// of the given one and can be modified freely.
+	CalledPartyAddress *params.PartyAddress
+	// Rule is the Rule that matched.
+	Rule *Rule
+	// Destination is the Destination chosen from the Rule.
+	Destination *Destination
+}
+
+// TranslationError is returned when the translation fails, and contains the
+// ReturnCause to be set in the UDTS/XUDTS/LUDTS.
+type TranslationError struct {
+	Cause   params.ReturnCauseValue
+	Address string
+}
+
+// Error returns the cause of the failure and the address that failed to translate.
+func (e *TranslationError) Error() string {
+	return fmt.Sprintf("gtt: %s: %q", e.Cause, e.Address)
+}
+
+// ReturnCause returns the Cause as a Return Cause parameter.
+func (e *TranslationError) ReturnCause() *params.ReturnCause {
+	return params.NewCause(e.Cause)
+}
+
+// Translator holds the translation rules and translates the Global Titles.
+//
+// It is safe for concurrent use, and the rules can be updated while translating.
+type Translator struct {
+	mu    sync.RWMutex
+	rules table[*Rule]
+
+	pausedPCs      map[uint32]struct{}
+	prohibitedSSNs map[subsystemKey]struct{}
+}
+
+// NewTranslator creates a new Translator with the given rules.
+func NewTranslator(rules ...*Rule) (*Translator, error) {
+	t := &Translator{
+		rules:          table[*Rule]{},
+		pausedPCs:      map[uint32]struct{}{},
+		prohibitedSSNs: map[subsystemKey]struct{}{},
+	}
+	for _, r := range rules {
+		if err := t.AddRule(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// AddRule adds the Rule to the Translator.
+// The existing Rule with the same Selector and Prefix is replaced.
+func (t *Translator) AddRule(r *Rule) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rules.add(r.Selector, r.Prefix, r)
+	return nil
+}
+
+// RemoveRule removes the Rule with the given Selector and Prefix from the Translator,
+// and reports whether the Rule existed.
+func (t *Translator) RemoveRule(sel Selector, prefix string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rules.remove(sel, prefix)
+}
+
+// Lookup returns the Rule whose Prefix is the longest match for the address
+// in the translation table of the Selector.
+//
+// The returned error is *TranslationError if no rule matches.
+func (t *Translator) Lookup(sel Selector, addr string) (*Rule, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.rules.lookup(sel, addr)
+}
+
+// Translate translates the Global Title in the given Called Party Address.
+//
+// The given PartyAddress is not modified. The returned error is *TranslationError
+// if the translation fails, which contains the ReturnCause to be returned to the
+// originator, e.g., ReturnCauseNoTranslationForThisSpecificAddress.
+//
+// Translate is the same as TranslateWithSLS with SLS 0.
+func (t *Translator) Translate(cdpa *params.PartyAddress) (*Result, error) {
+	return t.TranslateWithSLS(cdpa, 0)
+}
+
+// TranslateWithSLS translates the Global Title in the given Called Party Address,
+// choosing the Destination by the given Signaling Link Selection if the matched
+// Rule is load-shared by PolicySLS.
+//
+// The Destinations that are unavailable are skipped. If none of them is available,
+// the returned *TranslationError contains ReturnCauseMTPFailure or
+// ReturnCauseSubsystemFailure by the status of the first Destination.
+func (t *Translator) TranslateWithSLS(cdpa *params.PartyAddress, sls uint8) (*Result, error) {
+	if cdpa == nil || cdpa.GlobalTitle == nil || cdpa.GTI() == params.GTINoGT {
+		return nil, &TranslationError{Cause: params.ReturnCauseNoTranslationForAnAddressOfSuchNature}
+	}
+
+	r, err := t.Lookup(SelectorOf(cdpa.GlobalTitle), cdpa.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	d, cause, ok := t.choose(r, sls)
+	t.mu.RUnlock()
+	if !ok {
+		return nil, &TranslationError{Cause: cause, Address: cdpa.Address()}
+	}
+
+	res := &Result{DPC: d.DPC, Rule: r, Destination: d}
+	if d.CalledPartyAddress != nil {
+		res.CalledPartyAddress = d.CalledPartyAddress.Clone()
+		return res, nil
+	}
+
+	res.CalledPartyAddress = cdpa.Clone()
+	if d.SSN != 0 {
+		res.CalledPartyAddress.SetSubsystemNumber(d.SSN)
+	}
+	if d.RouteOnSSN {
+		res.CalledPartyAddress.SetRouteOnSSN(true)
+	}
+
+	return res, nil
+}
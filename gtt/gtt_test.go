@@ -0,0 +1,305 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/utils"
+)
+
+func newCdPA(ssn uint8, es params.EncodingScheme, addr []byte) *params.PartyAddress {
+	return params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, ssn,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI,
+			params.TranslationType(0),
+			params.NPISDNTelephony,
+			es,
+			params.NAIInternationalNumber,
+			addr,
+		),
+	)
+}
+
+func TestTranslator(t *testing.T) {
+	sel := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+	dpc1 := params.PointCode{Value: 0x0101, Width: params.PointCodeWidth14}
+	dpc2 := params.PointCode{Value: 0x0202, Width: params.PointCodeWidth14}
+	hlr := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(true, true, true, params.GTINoGT), 0x0303, 6, nil,
+	)
+
+	tr, err := gtt.NewTranslator(
+		&gtt.Rule{Selector: sel, Prefix: "", Destinations: []*gtt.Destination{{DPC: &dpc1}}},
+		&gtt.Rule{Selector: sel, Prefix: "8190", Destinations: []*gtt.Destination{{DPC: &dpc2, SSN: 7, RouteOnSSN: true}}},
+		&gtt.Rule{Selector: sel, Prefix: "819012", Destinations: []*gtt.Destination{{CalledPartyAddress: hlr}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		cdpa        *params.PartyAddress
+		expected    *params.PartyAddress
+		dpc         *params.PointCode
+		cause       params.ReturnCauseValue
+	}{
+		{
+			description: "default rule",
+			cdpa:        newCdPA(8, params.ESBCDEven, []byte{0x21, 0x43}),
+			expected:    newCdPA(8, params.ESBCDEven, []byte{0x21, 0x43}),
+			dpc:         &dpc1,
+		},
+		{
+			description: "SSN and RI",
+			cdpa:        newCdPA(8, params.ESBCDOdd, []byte{0x18, 0x09, 0xf3}),
+			expected: func() *params.PartyAddress {
+				p := newCdPA(7, params.ESBCDOdd, []byte{0x18, 0x09, 0xf3})
+				p.SetRouteOnSSN(true)
+				return p
+			}(),
+			dpc: &dpc2,
+		},
+		{
+			description: "CdPA replaced by longest match",
+			cdpa:        newCdPA(8, params.ESBCDEven, []byte{0x18, 0x09, 0x21, 0x43}),
+			expected:    hlr,
+		},
+		{
+			description: "no translation for such nature",
+			cdpa: params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 8,
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI, params.TranslationType(1), params.NPISDNTelephony,
+					params.ESBCDEven, params.NAIInternationalNumber, []byte{0x21, 0x43},
+				),
+			),
+			cause: params.ReturnCauseNoTranslationForAnAddressOfSuchNature,
+		},
+		{
+			description: "no GT",
+			cdpa:        hlr,
+			cause:       params.ReturnCauseNoTranslationForAnAddressOfSuchNature,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			res, err := tr.Translate(c.cdpa)
+			if c.expected == nil {
+				var terr *gtt.TranslationError
+				if !errors.As(err, &terr) {
+					t.Fatalf("expected TranslationError, got %v", err)
+				}
+				verify.Values(t, "", terr.Cause, c.cause)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verify.Values(t, "", res.CalledPartyAddress, c.expected)
+			verify.Values(t, "", res.DPC, c.dpc)
+		})
+	}
+
+	t.Run("no translation for this address", func(t *testing.T) {
+		if !tr.RemoveRule(sel, "") {
+			t.Fatal("default rule not removed")
+		}
+
+		_, err := tr.Translate(newCdPA(8, params.ESBCDEven, []byte{0x21, 0x43}))
+		var terr *gtt.TranslationError
+		if !errors.As(err, &terr) {
+			t.Fatalf("expected TranslationError, got %v", err)
+		}
+		verify.Values(t, "", terr.Cause, params.ReturnCauseNoTranslationForThisSpecificAddress)
+	})
+}
+
+func TestTranslatorDestinations(t *testing.T) {
+	sel := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+	pcs := []params.PointCode{
+		{Value: 0x0101, Width: params.PointCodeWidth14},
+		{Value: 0x0202, Width: params.PointCodeWidth14},
+		{Value: 0x0303, Width: params.PointCodeWidth14},
+	}
+	dests := func() []*gtt.Destination {
+		d := make([]*gtt.Destination, len(pcs))
+		for i := range pcs {
+			d[i] = &gtt.Destination{DPC: &pcs[i], SSN: 6, RouteOnSSN: true}
+		}
+		return d
+	}
+
+	tr, err := gtt.NewTranslator(
+		&gtt.Rule{Selector: sel, Prefix: "1", Mode: gtt.ModeDominant, Destinations: dests()},
+		&gtt.Rule{Selector: sel, Prefix: "2", Mode: gtt.ModeLoadShared, Policy: gtt.PolicySLS, Destinations: dests()},
+		&gtt.Rule{Selector: sel, Prefix: "3", Mode: gtt.ModeLoadShared, Policy: gtt.PolicyRoundRobin, Destinations: dests()},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translate := func(t *testing.T, addr []byte, sls uint8) (params.PointCode, error) {
+		t.Helper()
+		res, err := tr.TranslateWithSLS(newCdPA(8, params.ESBCDEven, addr), sls)
+		if err != nil {
+			return params.PointCode{}, err
+		}
+		return *res.DPC, nil
+	}
+
+	t.Run("dominant", func(t *testing.T) {
+		pc, _ := translate(t, []byte{0x21}, 0)
+		verify.Values(t, "primary", pc, pcs[0])
+
+		tr.SetPointCodeAvailable(pcs[0], false)
+		pc, _ = translate(t, []byte{0x21}, 0)
+		verify.Values(t, "backup", pc, pcs[1])
+
+		tr.SetSubsystemAvailable(pcs[1], 6, false)
+		pc, _ = translate(t, []byte{0x21}, 0)
+		verify.Values(t, "second backup", pc, pcs[2])
+
+		tr.SetPointCodeAvailable(pcs[2], false)
+		_, err := translate(t, []byte{0x21}, 0)
+		var terr *gtt.TranslationError
+		if !errors.As(err, &terr) {
+			t.Fatalf("expected TranslationError, got %v", err)
+		}
+		verify.Values(t, "cause", terr.Cause, params.ReturnCauseMTPFailure)
+
+		tr.SetPointCodeAvailable(pcs[0], true)
+		tr.SetSubsystemAvailable(pcs[1], 6, true)
+		tr.SetPointCodeAvailable(pcs[2], true)
+		pc, _ = translate(t, []byte{0x21}, 0)
+		verify.Values(t, "restored", pc, pcs[0])
+	})
+
+	t.Run("load-shared by SLS", func(t *testing.T) {
+		for sls := uint8(0); sls < 6; sls++ {
+			pc, _ := translate(t, []byte{0x12}, sls)
+			verify.Values(t, "", pc, pcs[int(sls)%len(pcs)])
+		}
+
+		tr.SetSubsystemAvailable(pcs[1], 6, false)
+		defer tr.SetSubsystemAvailable(pcs[1], 6, true)
+		for sls := uint8(0); sls < 6; sls++ {
+			pc, _ := translate(t, []byte{0x12}, sls)
+			if pc == pcs[1] {
+				t.Errorf("SLS %d: prohibited destination %s is chosen", sls, pc)
+			}
+			if sls%3 != 1 {
+				verify.Values(t, "", pc, pcs[int(sls)%len(pcs)])
+			}
+		}
+	})
+
+	t.Run("load-shared by round-robin", func(t *testing.T) {
+		tr.SetPointCodeAvailable(pcs[2], false)
+		defer tr.SetPointCodeAvailable(pcs[2], true)
+		for i := 0; i < 4; i++ {
+			pc, _ := translate(t, []byte{0x13}, 0)
+			verify.Values(t, "", pc, pcs[i%2])
+		}
+	})
+	t.Run("different width", func(t *testing.T) {
+		// e.g., the PC in MTP-PAUSE or SSP without the width of the variant.
+		tr.SetPointCodeAvailable(params.PointCode{Value: pcs[0].Value}, false)
+		defer tr.SetPointCodeAvailable(params.PointCode{Value: pcs[0].Value}, true)
+		pc, _ := translate(t, []byte{0x21}, 0)
+		verify.Values(t, "backup", pc, pcs[1])
+
+		tr.SetSubsystemAvailable(params.PointCode{Value: pcs[1].Value, Width: params.PointCodeWidth24}, 6, false)
+		defer tr.SetSubsystemAvailable(params.PointCode{Value: pcs[1].Value, Width: params.PointCodeWidth24}, 6, true)
+		pc, _ = translate(t, []byte{0x21}, 0)
+		verify.Values(t, "second backup", pc, pcs[2])
+	})
+}
+
+func TestRewriter(t *testing.T) {
+	nat := gtt.NewSelector(0, params.NPISDNTelephony, params.NAINationalSignificantNumber, params.ESBCDEven)
+	intl := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+	naiIntl := params.NAIInternationalNumber
+	onGT := false
+	pc := params.PointCode{Value: 0x0101, Width: params.PointCodeWidth14}
+
+	w, err := gtt.NewRewriter(
+		&gtt.RewriteRule{Selector: nat, Prefix: "0", Manipulation: gtt.Manipulation{
+			DeleteDigits: 1, AddPrefix: "44", NatureOfAddressIndicator: &naiIntl,
+		}},
+		&gtt.RewriteRule{Selector: intl, Prefix: "8190", Manipulation: gtt.Manipulation{
+			Address: "819000000", RouteOnSSN: &onGT, PointCode: &pc, StripSSN: true,
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newAddr := func(ai uint8, spc uint32, ssn uint8, es params.EncodingScheme, nai params.NatureOfAddressIndicator, digits string) *params.PartyAddress {
+		return params.NewCalledPartyAddress(ai, spc, ssn, params.NewGlobalTitle(
+			params.GTITTNPESNAI, 0, params.NPISDNTelephony, es, nai, utils.MustBCDEncode(digits),
+		))
+	}
+
+	cases := []struct {
+		description string
+		given       *params.PartyAddress
+		expected    *params.PartyAddress
+	}{
+		{
+			description: "national to international",
+			given: newAddr(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6,
+				params.ESBCDEven, params.NAINationalSignificantNumber, "0312345678",
+			),
+			expected: newAddr(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6,
+				params.ESBCDOdd, params.NAIInternationalNumber, "44312345678",
+			),
+		},
+		{
+			description: "replace address, add PC and strip SSN",
+			given: newAddr(
+				params.NewAddressIndicator(false, true, true, params.GTITTNPESNAI), 0, 6,
+				params.ESBCDEven, params.NAIInternationalNumber, "819012345678",
+			),
+			expected: newAddr(
+				params.NewAddressIndicator(true, false, false, params.GTITTNPESNAI), 0x0101, 0,
+				params.ESBCDOdd, params.NAIInternationalNumber, "819000000",
+			),
+		},
+		{
+			description: "no match",
+			given: newAddr(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6,
+				params.ESBCDEven, params.NAIInternationalNumber, "4412345678",
+			),
+			expected: newAddr(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6,
+				params.ESBCDEven, params.NAIInternationalNumber, "4412345678",
+			),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := w.Rewrite(c.given)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verify.Values(t, "", got, c.expected)
+			verify.Values(t, "serialized", got.MarshalBinary(), c.expected.MarshalBinary())
+		})
+	}
+}
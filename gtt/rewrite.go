@@ -0,0 +1,218 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Manipulation is a set of operations to rewrite a PartyAddress.
+//
+// The zero value does nothing, and only the non-zero fields are applied.
+type Manipulation struct {
+	// Address replaces the whole address digits in the GlobalTitle.
+	// If empty, DeleteDigits and AddPrefix are applied in this order instead.
+	Address string
+	// DeleteDigits is the number of the digits deleted from the beginning of the address.
+	DeleteDigits int
+	// AddPrefix is the digits added to the beginning of the address.
+	AddPrefix string
+
+	// GTI changes the format of the GlobalTitle. Note that the fields not included
+	// in the new format are not encoded.
+	GTI                      *params.GlobalTitleIndicator
+	TranslationType          *params.TranslationType
+	NumberingPlan            *params.NumberingPlan
+	NatureOfAddressIndicator *params.NatureOfAddressIndicator
+	EncodingScheme           *params.EncodingScheme
+
+	// RouteOnSSN sets the Routing Indicator to route on SSN if true, or on GT if false.
+	RouteOnSSN *bool
+	// PointCode adds the Signaling Point Code, or replaces the existing one.
+	PointCode *params.PointCode
+	// StripPointCode removes the Signaling Point Code.
+	StripPointCode bool
+	// SSN adds the Subsystem Number, or replaces the existing one.
+	SSN uint8
+	// StripSSN removes the Subsystem Number.
+	StripSSN bool
+}
+
+// validate checks if the Manipulation is properly configured.
+func (m *Manipulation) validate() error {
+	if err := validateDigits(m.Address); err != nil {
+		return err
+	}
+	if err := validateDigits(m.AddPrefix); err != nil {
+		return err
+	}
+	if m.DeleteDigits < 0 {
+		return fmt.Errorf("gtt: invalid number of digits to delete: %d", m.DeleteDigits)
+	}
+
+	if m.PointCode != nil {
+		if m.StripPointCode {
+			return fmt.Errorf("gtt: PointCode and StripPointCode are exclusive")
+		}
+		if err := m.PointCode.Validate(); err != nil {
+			return fmt.Errorf("gtt: %w", err)
+		}
+	}
+	if m.SSN != 0 && m.StripSSN {
+		return fmt.Errorf("gtt: SSN and StripSSN are exclusive")
+	}
+
+	return nil
+}
+
+// Apply applies the Manipulation to the given PartyAddress.
+//
+// The Indicator and the length of the PartyAddress are updated accordingly.
+// The digits are manipulated only when the PartyAddress has a GlobalTitle.
+func (m *Manipulation) Apply(p *params.PartyAddress) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	if gt := p.GlobalTitle; gt != nil {
+		if m.GTI != nil {
+			gt.GTI = *m.GTI
+		}
+		if m.TranslationType != nil {
+			gt.TranslationType = *m.TranslationType
+		}
+		if m.NumberingPlan != nil {
+			gt.NumberingPlan = *m.NumberingPlan
+		}
+		if m.NatureOfAddressIndicator != nil {
+			gt.NatureOfAddressIndicator = *m.NatureOfAddressIndicator
+		}
+		if m.EncodingScheme != nil {
+			gt.EncodingScheme = *m.EncodingScheme
+		}
+
+		addr := gt.Address()
+		if m.Address != "" {
+			addr = m.Address
+		} else {
+			if m.DeleteDigits > len(addr) {
+				return fmt.Errorf("gtt: cannot delete %d digits from %q", m.DeleteDigits, addr)
+			}
+			addr = m.AddPrefix + addr[m.DeleteDigits:]
+		}
+		if err := gt.SetAddress(addr); err != nil {
+			return fmt.Errorf("gtt: %w", err)
+		}
+
+		p.SetGlobalTitle(gt)
+	}
+
+	if m.RouteOnSSN != nil {
+		p.SetRouteOnSSN(*m.RouteOnSSN)
+	}
+
+	if m.PointCode != nil {
+		p.SetSignalingPointCode(*m.PointCode)
+	}
+	if m.StripPointCode {
+		p.RemoveSignalingPointCode()
+	}
+
+	if m.SSN != 0 {
+		p.SetSubsystemNumber(m.SSN)
+	}
+	if m.StripSSN {
+		p.SetSubsystemNumber(0)
+	}
+
+	p.SetLength()
+	return nil
+}
+
+// RewriteRule is a rule to rewrite the PartyAddress that has the GlobalTitle of the
+// Selector whose address begins with Prefix.
+//
+// An empty Prefix matches any address, which can be used as the default rule.
+type RewriteRule struct {
+	Selector     Selector
+	Prefix       string
+	Manipulation Manipulation
+}
+
+// Rewriter holds the RewriteRules and rewrites the PartyAddresses, which can be used
+// e.g., to normalize the national numbers into international ones, or to rewrite the
+// Calling Party Address when relaying the messages.
+//
+// It is safe for concurrent use, and the rules can be updated while rewriting.
+type Rewriter struct {
+	mu    sync.RWMutex
+	rules table[*RewriteRule]
+}
+
+// NewRewriter creates a new Rewriter with the given rules.
+func NewRewriter(rules ...*RewriteRule) (*Rewriter, error) {
+	w := &Rewriter{rules: table[*RewriteRule]{}}
+	for _, r := range rules {
+		if err := w.AddRule(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// AddRule adds the RewriteRule to the Rewriter.
+// The existing RewriteRule with the same Selector and Prefix is replaced.
+func (w *Rewriter) AddRule(r *RewriteRule) error {
+	if err := validateDigits(r.Prefix); err != nil {
+		return err
+	}
+	if err := r.Manipulation.validate(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.rules.add(r.Selector, r.Prefix, r)
+	return nil
+}
+
+// RemoveRule removes the RewriteRule with the given Selector and Prefix from the Rewriter,
+// and reports whether the RewriteRule existed.
+func (w *Rewriter) RemoveRule(sel Selector, prefix string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rules.remove(sel, prefix)
+}
+
+// Rewrite rewrites the given PartyAddress by the RewriteRule whose Prefix is the
+// longest match for the address, and returns the rewritten copy of it.
+//
+// The given PartyAddress is not modified. If it has no GlobalTitle or no rule
+// matches, it is returned as it is.
+func (w *Rewriter) Rewrite(p *params.PartyAddress) (*params.PartyAddress, error) {
+	if p == nil || p.GlobalTitle == nil || p.GTI() == params.GTINoGT {
+		return p, nil
+	}
+
+	w.mu.RLock()
+	r, err := w.rules.lookup(SelectorOf(p.GlobalTitle), p.Address())
+	w.mu.RUnlock()
+	if err != nil {
+		return p, nil
+	}
+
+	rewritten := p.Clone()
+	if err := r.Manipulation.Apply(rewritten); err != nil {
+		return nil, err
+	}
+
+	return rewritten, nil
+}
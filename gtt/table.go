@@ -0,0 +1,74 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// table holds the values selected by the Selector and the longest match of the
+// prefix of the address. It is not safe for concurrent use by itself.
+type table[T any] map[Selector]map[string]T
+
+// add adds the value to the table, replacing the existing one.
+func (t table[T]) add(sel Selector, prefix string, v T) {
+	sel = sel.normalize()
+	if t[sel] == nil {
+		t[sel] = map[string]T{}
+	}
+	t[sel][prefix] = v
+}
+
+// remove removes the value from the table and reports whether it existed.
+func (t table[T]) remove(sel Selector, prefix string) bool {
+	sel = sel.normalize()
+	if _, ok := t[sel][prefix]; !ok {
+		return false
+	}
+
+	delete(t[sel], prefix)
+	if len(t[sel]) == 0 {
+		delete(t, sel)
+	}
+	return true
+}
+
+// lookup returns the value whose prefix is the longest match for the address.
+//
+// The returned error is *TranslationError if no value matches.
+func (t table[T]) lookup(sel Selector, addr string) (T, error) {
+	var zero T
+
+	prefixes, ok := t[sel.normalize()]
+	if !ok {
+		return zero, &TranslationError{
+			Cause:   params.ReturnCauseNoTranslationForAnAddressOfSuchNature,
+			Address: addr,
+		}
+	}
+
+	for i := len(addr); i >= 0; i-- {
+		if v, ok := prefixes[addr[:i]]; ok {
+			return v, nil
+		}
+	}
+
+	return zero, &TranslationError{
+		Cause:   params.ReturnCauseNoTranslationForThisSpecificAddress,
+		Address: addr,
+	}
+}
+
+// validateDigits checks if the given string consists of decimal digits only.
+func validateDigits(s string) error {
+	for _, d := range s {
+		if d < '0' || d > '9' {
+			return fmt.Errorf("gtt: invalid digit %q in %q", d, s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// IT represents a SCCP Message Inactivity test (IT).
+type IT struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ProtocolClass             *params.ProtocolClass
+	SequencingSegmenting      *params.SequencingSegmenting
+	Credit                    *params.Credit
+}
+
+// NewIT creates a new IT.
+// P(S) and P(R) are given in 0-127, and shifted to the left by 1 in SequencingSegmenting.
+func NewIT(dlr, slr uint32, pcls int, ps, pr uint8, moreData bool, credit uint8) *IT {
+	return &IT{
+		Type:                      MsgTypeIT,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ProtocolClass:             params.NewProtocolClass(pcls, false),
+		SequencingSegmenting:      params.NewSequencingSegmentingFromSequence(ps, pr, moreData),
+		Credit:                    params.NewCredit(credit),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an IT instance.
+func (i *IT) MarshalBinary() ([]byte, error) {
+	b := make([]byte, i.MarshalLen())
+	if err := i.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (i *IT) MarshalTo(b []byte) error {
+	if len(b) < i.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(i.Type)
+
+	n := 1
+	m, err := i.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = i.SourceLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = i.ProtocolClass.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = i.SequencingSegmenting.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := i.Credit.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseIT decodes given byte sequence as a SCCP IT.
+func ParseIT(b []byte) (*IT, error) {
+	i := &IT{}
+	if err := i.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP IT.
+func (i *IT) UnmarshalBinary(b []byte) error {
+	if len(b) < 11 {
+		return io.ErrUnexpectedEOF
+	}
+
+	i.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	i.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	i.SourceLocalReference, n, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	i.ProtocolClass, n, err = params.ParseProtocolClass(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	i.SequencingSegmenting, n, err = params.ParseSequencingSegmenting(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	i.Credit, _, err = params.ParseCredit(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (i *IT) MarshalLen() int {
+	return 11 // MsgType + DestinationLocalReference + SourceLocalReference + ProtocolClass + SequencingSegmenting + Credit
+}
+
+// String returns the IT values in human readable format.
+func (i *IT) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ProtocolClass: %s, SequencingSegmenting: %s, Credit: %s}",
+		i.Type,
+		i.DestinationLocalReference,
+		i.SourceLocalReference,
+		i.ProtocolClass,
+		i.SequencingSegmenting,
+		i.Credit,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (i *IT) MessageType() MsgType {
+	return MsgTypeIT
+}
+
+// MessageTypeName returns the Message Type in string.
+func (i *IT) MessageTypeName() string {
+	return i.MessageType().String()
+}
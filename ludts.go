@@ -0,0 +1,365 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// LUDTS represents a SCCP Message Long unitdata service (LUDTS).
+//
+// As well as LUDT, the pointers in LUDTS are two octets long.
+type LUDTS struct {
+	Type                    MsgType
+	ReturnCause             *params.ReturnCause
+	HopCounter              *params.HopCounter
+	CalledPartyAddress      *params.PartyAddress
+	CallingPartyAddress     *params.PartyAddress
+	LongData                *params.LongData
+	Segmentation            *params.Segmentation
+	Importance              *params.Importance
+	EndOfOptionalParameters *params.EndOfOptionalParameters
+
+	ptr1, ptr2, ptr3, ptr4 uint16
+}
+
+// NewLUDTS creates a new LUDTS.
+//
+// The data longer than MaxLongDataLen fail with ErrTooLongData on MarshalTo.
+func NewLUDTS(cause params.ReturnCauseValue, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) *LUDTS {
+	if len(data) > MaxLongDataLen {
+		logf("too long data in NewLUDTS: %d octets, must be <= %d", len(data), MaxLongDataLen)
+	}
+
+	l := &LUDTS{
+		Type:                MsgTypeLUDTS,
+		ReturnCause:         params.NewCause(cause),
+		HopCounter:          params.NewHopCounter(hc),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		LongData:            params.NewLongData(data),
+	}
+
+	l.ptr1 = 8
+	l.ptr2 = l.ptr1 + uint16(cdpa.MarshalLen()) - 2
+	l.ptr3 = l.ptr2 + uint16(cgpa.MarshalLen()) - 2
+	l.ptr4 = 0
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			l.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			logf("unexpected parameter: %s in NewLUDTS", opt.Code())
+		}
+	}
+
+	if len(opts) > 0 {
+		l.ptr4 = l.ptr3 + uint16(l.LongData.MarshalLen()) - 2
+		// so that users don't have to give EndOfOptionalParameters explicitly
+		l.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
+	}
+
+	return l
+}
+
+// MarshalBinary returns the byte sequence generated from a LUDTS instance.
+func (l *LUDTS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, l.MarshalLen())
+	if err := l.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (l *LUDTS) MarshalTo(b []byte) error {
+	n := len(b)
+	if n < 11 {
+		return io.ErrUnexpectedEOF
+	}
+	if len(l.LongData.Value()) > MaxLongDataLen {
+		return ErrTooLongData
+	}
+
+	b[0] = uint8(l.Type)
+
+	offset := 1
+	m, err := l.ReturnCause.Write(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += m
+
+	m, err = l.HopCounter.Write(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += m
+
+	binary.LittleEndian.PutUint16(b[offset:offset+2], l.ptr1)
+	binary.LittleEndian.PutUint16(b[offset+2:offset+4], l.ptr2)
+	binary.LittleEndian.PutUint16(b[offset+4:offset+6], l.ptr3)
+	binary.LittleEndian.PutUint16(b[offset+6:offset+8], l.ptr4)
+	offset += 8
+
+	cdpaEnd := 5 + int(l.ptr2)
+	if n < cdpaEnd {
+		return io.ErrUnexpectedEOF
+	}
+	cgpaEnd := 7 + int(l.ptr3)
+	if n < cgpaEnd {
+		return io.ErrUnexpectedEOF
+	}
+
+	if _, err := l.CalledPartyAddress.Write(b[offset:cdpaEnd]); err != nil {
+		return err
+	}
+
+	if _, err := l.CallingPartyAddress.Write(b[cdpaEnd:cgpaEnd]); err != nil {
+		return err
+	}
+
+	if _, err := l.LongData.Write(b[cgpaEnd:]); err != nil {
+		return err
+	}
+
+	if l.ptr4 == 0 {
+		return nil
+	}
+
+	offset = 9 + int(l.ptr4)
+	if n < offset {
+		return io.ErrUnexpectedEOF
+	}
+	if param := l.Segmentation; param != nil {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+	if param := l.Importance; param != nil {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+	if param := l.EndOfOptionalParameters; param != nil {
+		_, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseLUDTS decodes given byte sequence as a SCCP LUDTS.
+func ParseLUDTS(b []byte) (*LUDTS, error) {
+	return ParseLUDTSWithVariant(params.VariantITU, b)
+}
+
+// ParseLUDTSWithVariant decodes given byte sequence as a SCCP LUDTS in the given protocol variant.
+func ParseLUDTSWithVariant(v params.Variant, b []byte) (*LUDTS, error) {
+	l := &LUDTS{}
+	if err := l.unmarshalBinaryWithVariant(v, b); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP LUDTS.
+func (l *LUDTS) UnmarshalBinary(b []byte) error {
+	return l.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP LUDTS,
+// decoding the parameters in the given protocol variant.
+func (l *LUDTS) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
+	n := len(b)
+	if n < 11 {
+		return io.ErrUnexpectedEOF
+	}
+
+	l.Type = MsgType(b[0])
+
+	offset := 1
+	l.ReturnCause = &params.ReturnCause{}
+	m, err := l.ReturnCause.Read(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += m
+
+	l.HopCounter = &params.HopCounter{}
+	m, err = l.HopCounter.Read(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += m
+
+	l.ptr1 = binary.LittleEndian.Uint16(b[offset : offset+2])
+	offsetPtr1 := 3 + int(l.ptr1)
+	if n < offsetPtr1+1 { // where CdPA starts
+		return io.ErrUnexpectedEOF
+	}
+	l.ptr2 = binary.LittleEndian.Uint16(b[offset+2 : offset+4])
+	offsetPtr2 := 5 + int(l.ptr2)
+	if n < offsetPtr2+1 { // where CgPA starts
+		return io.ErrUnexpectedEOF
+	}
+	l.ptr3 = binary.LittleEndian.Uint16(b[offset+4 : offset+6])
+	offsetPtr3 := 7 + int(l.ptr3)
+	if n < offsetPtr3+2 { // where LongData starts
+		return io.ErrUnexpectedEOF
+	}
+	l.ptr4 = binary.LittleEndian.Uint16(b[offset+6 : offset+8])
+	offsetPtr4 := 9 + int(l.ptr4)
+	if l.ptr4 != 0 && n < offsetPtr4+1 { // where optional parameters start
+		return io.ErrUnexpectedEOF
+	}
+
+	cdpaEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if n < cdpaEnd {                               // where CdPA ends
+		return io.ErrUnexpectedEOF
+	}
+	cgpaEnd := offsetPtr2 + int(b[offsetPtr2]) + 1
+	if n < cgpaEnd { // where CgPA ends
+		return io.ErrUnexpectedEOF
+	}
+	dataLen := int(binary.LittleEndian.Uint16(b[offsetPtr3 : offsetPtr3+2]))
+	if dataLen > MaxLongDataLen {
+		return ErrTooLongData
+	}
+	dataEnd := offsetPtr3 + dataLen + 2 // +2 is the two-octet length included from the beginning
+	if n < dataEnd {                    // where LongData ends
+		return io.ErrUnexpectedEOF
+	}
+
+	// the parameters must be placed in the order without gaps as the pointers are
+	// reused in MarshalTo.
+	if offsetPtr1 != offset+8 || offsetPtr2 != cdpaEnd || offsetPtr3 != cgpaEnd ||
+		(l.ptr4 != 0 && offsetPtr4 != dataEnd) {
+		return io.ErrUnexpectedEOF
+	}
+
+	l.CalledPartyAddress, _, err = params.ParseCalledPartyAddressWithVariant(v, b[offsetPtr1:cdpaEnd])
+	if err != nil {
+		return err
+	}
+
+	l.CallingPartyAddress, _, err = params.ParseCallingPartyAddressWithVariant(v, b[offsetPtr2:cgpaEnd])
+	if err != nil {
+		return err
+	}
+
+	l.LongData, _, err = params.ParseLongData(b[offsetPtr3:dataEnd])
+	if err != nil {
+		return err
+	}
+
+	if l.ptr4 == 0 {
+		return nil
+	}
+
+	opts, _, err := params.ParseOptionalParametersWithVariant(v, b[offsetPtr4:])
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			l.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (l *LUDTS) MarshalLen() int {
+	n := 11 // MsgType + ReturnCause + HopCounter + Pointers
+
+	// if optional parameters exist
+	if l.ptr4 != 0 {
+		n += int(l.ptr4) - 2 // length without optional parameters
+		if param := l.Segmentation; param != nil {
+			n += param.MarshalLen()
+		}
+		if param := l.Importance; param != nil {
+			n += param.MarshalLen()
+		}
+		if param := l.EndOfOptionalParameters; param != nil {
+			n += param.MarshalLen()
+		}
+
+		return n
+	}
+
+	n += int(l.ptr3) - 4 // length without LongData
+	if param := l.LongData; param != nil {
+		n += param.MarshalLen()
+	}
+
+	return n
+}
+
+// String returns the LUDTS values in human readable format.
+func (l *LUDTS) String() string {
+	return fmt.Sprintf("%s: {ReturnCause: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, LongData: %s, Segmentation: %s, Importance: %s}",
+		l.Type,
+		l.ReturnCause,
+		l.HopCounter,
+		l.CalledPartyAddress,
+		l.CallingPartyAddress,
+		l.LongData,
+		l.Segmentation,
+		l.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (l *LUDTS) MessageType() MsgType {
+	return MsgTypeLUDTS
+}
+
+// MessageTypeName returns the Message Type in string.
+func (l *LUDTS) MessageTypeName() string {
+	return l.MessageType().String()
+}
+
+// CdGT returns the GT in CalledPartyAddress in human readable string.
+func (l *LUDTS) CdGT() string {
+	if l.CalledPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return l.CalledPartyAddress.Address()
+}
+
+// CgGT returns the GT in CalledPartyAddress in human readable string.
+func (l *LUDTS) CgGT() string {
+	if l.CallingPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return l.CallingPartyAddress.Address()
+}
@@ -0,0 +1,97 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mtp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wmnsk/go-m3ua"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// M3UA is the SAP over the M3UA connection of go-m3ua.
+//
+// The point codes in the M3UA Protocol Data are decoded in the width of the Variant.
+// As go-m3ua does not notify the SSNM messages (e.g., DUNA and DAVA) to the user,
+// MTP-PAUSE, MTP-RESUME and MTP-STATUS should be given with Indicate if needed.
+type M3UA struct {
+	Variant params.Variant
+
+	conn *m3ua.Conn
+	q    *queue
+	once sync.Once
+}
+
+// NewM3UA creates a new M3UA SAP over the established M3UA connection.
+func NewM3UA(v params.Variant, conn *m3ua.Conn) *M3UA {
+	return &M3UA{
+		Variant: v,
+		conn:    conn,
+		q:       newQueue(),
+	}
+}
+
+// Send sends the user data in the M3UA DATA message.
+func (m *M3UA) Send(ctx context.Context, t *Transfer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := m.conn.WritePD(m3params.NewProtocolData(
+		t.OPC.Value, t.DPC.Value, uint8(t.SI), t.NI, t.MP, t.SLS, t.Data,
+	))
+	return err
+}
+
+// Receive blocks until a Primitive is delivered, which is the user data in the
+// M3UA DATA message or the one given with Indicate.
+//
+// Once the M3UA connection fails, the error is returned after the Primitives
+// already received.
+func (m *M3UA) Receive(ctx context.Context) (Primitive, error) {
+	m.once.Do(func() {
+		go m.serve()
+	})
+
+	return m.q.get(ctx)
+}
+
+// Indicate delivers the Primitive to the user, which can be used to notify
+// MTP-PAUSE, MTP-RESUME and MTP-STATUS.
+func (m *M3UA) Indicate(ctx context.Context, prim Primitive) error {
+	return m.q.put(ctx, prim)
+}
+
+// Close closes the M3UA connection.
+func (m *M3UA) Close() error {
+	m.q.close(ErrClosed)
+	return m.conn.Close()
+}
+
+// serve reads the M3UA connection until it fails.
+func (m *M3UA) serve() {
+	for {
+		pd, err := m.conn.ReadPD()
+		if err != nil {
+			m.q.close(err)
+			return
+		}
+
+		if err := m.q.put(context.Background(), &Transfer{
+			OPC:  m.Variant.NewPointCode(pd.OriginatingPointCode),
+			DPC:  m.Variant.NewPointCode(pd.DestinationPointCode),
+			SI:   ServiceIndicator(pd.ServiceIndicator),
+			NI:   pd.NetworkIndicator,
+			MP:   pd.MessagePriority,
+			SLS:  pd.SignalingLinkSelection,
+			Data: pd.Data,
+		}); err != nil {
+			return
+		}
+	}
+}
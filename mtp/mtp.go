@@ -0,0 +1,139 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package mtp provides the interface to the MTP (Message Transfer Part) service
+// that SCCP is built on, i.e., MTP3 or its SIGTRAN counterpart such as M3UA.
+//
+// The primitives are defined in Q.701 8.
+package mtp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ErrClosed is returned when the SAP is already closed.
+var ErrClosed = errors.New("mtp: SAP closed")
+
+// ServiceIndicator is a Service Indicator in the Service Information Octet, which
+// identifies the MTP user.
+type ServiceIndicator uint8
+
+// ServiceIndicator value for SCCP. See Q.704 14.2.1 for the others.
+const ServiceIndicatorSCCP ServiceIndicator = 3
+
+// Primitive is a MTP service primitive delivered to the MTP user, which is one of
+// *Transfer, *Pause, *Resume and *Status.
+type Primitive interface {
+	fmt.Stringer
+	primitive()
+}
+
+// Transfer is the set of the parameters of MTP-TRANSFER request/indication, i.e.,
+// the user data with the routing label and the Service Information Octet.
+type Transfer struct {
+	OPC  params.PointCode
+	DPC  params.PointCode
+	SI   ServiceIndicator
+	NI   uint8
+	MP   uint8
+	SLS  uint8
+	Data []byte
+}
+
+func (t *Transfer) primitive() {}
+
+// String returns the Transfer in a human-readable format.
+func (t *Transfer) String() string {
+	return fmt.Sprintf("MTP-TRANSFER: {OPC: %s, DPC: %s, SI: %d, NI: %d, MP: %d, SLS: %d, Data: %x}",
+		t.OPC, t.DPC, t.SI, t.NI, t.MP, t.SLS, t.Data,
+	)
+}
+
+// Pause is MTP-PAUSE indication, which notifies that the signaling point is inaccessible.
+type Pause struct {
+	AffectedPC params.PointCode
+}
+
+func (p *Pause) primitive() {}
+
+// String returns the Pause in a human-readable format.
+func (p *Pause) String() string {
+	return fmt.Sprintf("MTP-PAUSE: {AffectedPC: %s}", p.AffectedPC)
+}
+
+// Resume is MTP-RESUME indication, which notifies that the signaling point is accessible.
+type Resume struct {
+	AffectedPC params.PointCode
+}
+
+func (r *Resume) primitive() {}
+
+// String returns the Resume in a human-readable format.
+func (r *Resume) String() string {
+	return fmt.Sprintf("MTP-RESUME: {AffectedPC: %s}", r.AffectedPC)
+}
+
+// StatusCause is the cause of MTP-STATUS indication.
+type StatusCause uint8
+
+// StatusCause values.
+const (
+	// StatusCauseCongestion indicates that the signaling network is congested.
+	StatusCauseCongestion StatusCause = iota
+	// StatusCauseUserUnavailableUnknown indicates that the remote user is unavailable
+	// for an unknown reason.
+	StatusCauseUserUnavailableUnknown
+	// StatusCauseUserUnavailableUnequipped indicates that the remote user is unequipped.
+	StatusCauseUserUnavailableUnequipped
+	// StatusCauseUserUnavailableInaccessible indicates that the remote user is inaccessible.
+	StatusCauseUserUnavailableInaccessible
+)
+
+// String returns the StatusCause in a human-readable format.
+func (c StatusCause) String() string {
+	switch c {
+	case StatusCauseCongestion:
+		return "signaling network congested"
+	case StatusCauseUserUnavailableUnknown:
+		return "remote user unavailable (unknown)"
+	case StatusCauseUserUnavailableUnequipped:
+		return "remote user unavailable (unequipped)"
+	case StatusCauseUserUnavailableInaccessible:
+		return "remote user unavailable (inaccessible)"
+	default:
+		return fmt.Sprintf("StatusCause(%d)", c)
+	}
+}
+
+// Status is MTP-STATUS indication, which notifies that the signaling network is
+// congested or the remote user is unavailable at the signaling point.
+type Status struct {
+	AffectedPC params.PointCode
+	Cause      StatusCause
+	// CongestionLevel is the level of the congestion in StatusCauseCongestion,
+	// which is used only in the national options with multiple congestion levels.
+	CongestionLevel uint8
+}
+
+func (s *Status) primitive() {}
+
+// String returns the Status in a human-readable format.
+func (s *Status) String() string {
+	return fmt.Sprintf("MTP-STATUS: {AffectedPC: %s, Cause: %s, CongestionLevel: %d}",
+		s.AffectedPC, s.Cause, s.CongestionLevel,
+	)
+}
+
+// SAP is the MTP Service Access Point used by SCCP.
+type SAP interface {
+	// Send sends the user data to the DPC, i.e., MTP-TRANSFER request.
+	Send(ctx context.Context, t *Transfer) error
+	// Receive blocks until a Primitive is delivered, i.e., MTP-TRANSFER, MTP-PAUSE,
+	// MTP-RESUME or MTP-STATUS indication, or the ctx is done.
+	Receive(ctx context.Context) (Primitive, error)
+}
@@ -0,0 +1,50 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mtp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestPipe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a, b := mtp.NewPipe()
+	opc, dpc := params.VariantITU.NewPointCode(1), params.VariantITU.NewPointCode(2)
+
+	tr := &mtp.Transfer{OPC: opc, DPC: dpc, SI: mtp.ServiceIndicatorSCCP, SLS: 3, Data: []byte{0xde, 0xad}}
+	if err := a.Send(ctx, tr); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Indicate(ctx, &mtp.Pause{AffectedPC: opc}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []mtp.Primitive{tr, &mtp.Pause{AffectedPC: opc}} {
+		got, err := b.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "", got, expected)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Receive(ctx); !errors.Is(err, mtp.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := a.Send(ctx, tr); !errors.Is(err, mtp.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mtp
+
+import (
+	"context"
+	"sync"
+)
+
+// queueSize is the number of the Primitives that can be queued without blocking.
+const queueSize = 64
+
+// queue is a queue of the Primitives to be received by the MTP user.
+type queue struct {
+	ch   chan Primitive
+	done chan struct{}
+	once sync.Once
+	err  error
+}
+
+func newQueue() *queue {
+	return &queue{
+		ch:   make(chan Primitive, queueSize),
+		done: make(chan struct{}),
+	}
+}
+
+// put puts the Primitive in the queue, blocking while the queue is full.
+func (q *queue) put(ctx context.Context, p Primitive) error {
+	select {
+	case <-q.done:
+		return q.err
+	default:
+	}
+
+	select {
+	case q.ch <- p:
+		return nil
+	case <-q.done:
+		return q.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// get gets the Primitive from the queue, blocking while the queue is empty.
+// The Primitives queued before closing are returned before the error.
+func (q *queue) get(ctx context.Context) (Primitive, error) {
+	select {
+	case p := <-q.ch:
+		return p, nil
+	default:
+	}
+
+	select {
+	case p := <-q.ch:
+		return p, nil
+	case <-q.done:
+		return nil, q.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// close closes the queue with the error returned afterwards.
+func (q *queue) close(err error) {
+	q.once.Do(func() {
+		q.err = err
+		close(q.done)
+	})
+}
+
+// Pipe is an end of the in-memory MTP, which delivers the MTP-TRANSFER requests to
+// the other end as they are, regardless of the DPC.
+//
+// It can be used to test the MTP users without the underlying network.
+type Pipe struct {
+	q    *queue
+	peer *Pipe
+}
+
+// NewPipe creates a pair of the connected Pipes.
+func NewPipe() (*Pipe, *Pipe) {
+	a := &Pipe{q: newQueue()}
+	b := &Pipe{q: newQueue(), peer: a}
+	a.peer = b
+	return a, b
+}
+
+// Send sends the user data to the other end.
+func (p *Pipe) Send(ctx context.Context, t *Transfer) error {
+	return p.peer.q.put(ctx, t)
+}
+
+// Receive blocks until a Primitive is delivered to the end.
+func (p *Pipe) Receive(ctx context.Context) (Primitive, error) {
+	return p.q.get(ctx)
+}
+
+// Indicate delivers the Primitive to the user of the end, which can be used to
+// notify MTP-PAUSE, MTP-RESUME and MTP-STATUS.
+func (p *Pipe) Indicate(ctx context.Context, prim Primitive) error {
+	return p.q.put(ctx, prim)
+}
+
+// Close closes both ends of the Pipe.
+//
+// The Primitives already delivered can still be received, and ErrClosed is
+// returned afterwards.
+func (p *Pipe) Close() error {
+	p.q.close(ErrClosed)
+	p.peer.q.close(ErrClosed)
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Indication is the N-UNITDATA indication, or the N-NOTICE indication if ReturnCause
+// is set, delivered to a local subsystem.
+type Indication struct {
+	// OPC is the point code of the node the Message is received from, which is the
+	// local PointCode if the Message is originated in the local node.
+	OPC params.PointCode
+	SLS uint8
+	// Message is the Message as it is received.
+	Message Message
+	// CalledPartyAddress is the Called Party Address after the translation.
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                []byte
+	// ReturnCause is set only when the Message is UDTS, XUDTS or LUDTS.
+	ReturnCause *params.ReturnCause
+}
+
+// ssnSCMG is the Subsystem Number of the SCCP management.
+const ssnSCMG = 1
+
+// Handler handles the Indication delivered to a local subsystem.
+type Handler func(ind *Indication)
+
+// Node is a SCCP node that implements the SCCP Routing Control (SCRC) in Q.714 2.3.
+//
+// It owns a local PointCode and a set of local subsystems, receives the connectionless
+// messages (UDT, XUDT, LUDT and their service messages) from the MTP, and delivers them
+// to the local subsystems or relays them to other nodes after the translation.
+//
+// It also implements the SCCP Connection Oriented Control (SCOC) in Q.714 3 for the
+// local subsystems. See Connect and RegisterConnectHandler.
+type Node struct {
+	Variant          params.Variant
+	PointCode        params.PointCode
+	NetworkIndicator uint8
+	// Translator is used to route the messages on Global Title. If nil, such
+	// messages fail with ReturnCauseNoTranslationForAnAddressOfSuchNature.
+	Translator *gtt.Translator
+	// Reassembler is used to reassemble the segmented messages to the local subsystems.
+	// If nil, the segments are delivered as they are. The XUDTS or LUDTS on T(reass)
+	// expiry is not returned by the Node, and should be sent with Send in its Expired,
+	// which is addressed to the originator with the OPC of the segments.
+	Reassembler *Reassembler
+	// Timers is used in the connection-oriented procedures.
+	// It should not be modified after starting Serve.
+	Timers Timers
+	// Credit is the window size proposed in the protocol class 3 connections.
+	Credit uint8
+
+	mtp          mtp.SAP
+	mu           sync.RWMutex
+	handlers     map[uint8]Handler
+	connHandlers map[uint8]*ConnectHandler // pointers to identify the registrations
+	conns        map[uint32]*Connection
+	frozen       map[uint32]struct{}
+	thaws        []thaw
+	lastRef      uint32
+}
+
+// NewNode creates a new Node that has the given PointCode and works on the given MTP SAP.
+func NewNode(v params.Variant, pc params.PointCode, sap mtp.SAP) *Node {
+	return &Node{
+		Variant:      v,
+		PointCode:    pc,
+		Timers:       DefaultTimers,
+		Credit:       defaultCredit,
+		mtp:          sap,
+		handlers:     map[uint8]Handler{},
+		connHandlers: map[uint8]*ConnectHandler{},
+		conns:        map[uint32]*Connection{},
+		frozen:       map[uint32]struct{}{},
+	}
+}
+
+// Register registers the Handler for the local subsystem.
+// The existing Handler for the subsystem is replaced.
+func (n *Node) Register(ssn uint8, h Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.handlers[ssn] = h
+}
+
+// Unregister unregisters the Handler and the ConnectHandler for the local subsystem.
+func (n *Node) Unregister(ssn uint8) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.handlers, ssn)
+	delete(n.connHandlers, ssn)
+}
+
+func (n *Node) handler(ssn uint8) Handler {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.handlers[ssn]
+}
+
+// Serve receives the primitives from the MTP and handles them until the ctx is done
+// or the MTP fails, and returns the error.
+//
+// MTP-PAUSE and MTP-RESUME update the accessibility of the point code in the Translator,
+// so that the inaccessible destinations are skipped in the translation. In the same way,
+// SSP and SSA received in the SCMG (SSN 1) update the availability of the affected
+// subsystem. The SCMG is also delivered to the Handler for SSN 1 if registered.
+// The Handlers are called in the same goroutine as Serve.
+func (n *Node) Serve(ctx context.Context) error {
+	for {
+		prim, err := n.mtp.Receive(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch p := prim.(type) {
+		case *mtp.Transfer:
+			n.handle(ctx, p)
+		case *mtp.Pause:
+			if n.Translator != nil {
+				n.Translator.SetPointCodeAvailable(p.AffectedPC, false)
+			}
+		case *mtp.Resume:
+			if n.Translator != nil {
+				n.Translator.SetPointCodeAvailable(p.AffectedPC, true)
+			}
+		default:
+			logf("ignored %s", prim)
+		}
+	}
+}
+
+// Send sends the connectionless message (UDT, XUDT, LUDT or their service messages)
+// from the local node, i.e., N-UNITDATA request.
+//
+// The message is routed by its Called Party Address in the same way as the received
+// ones. If it fails, the returned error is *RoutingError, and no service message is sent.
+func (n *Node) Send(ctx context.Context, m Message, sls uint8) error {
+	u, ok := newUnitdata(m)
+	if !ok {
+		return UnsupportedTypeError(m.MessageType())
+	}
+
+	return n.transfer(ctx, u, m, n.PointCode, sls)
+}
+
+// handle handles the MTP-TRANSFER indication.
+func (n *Node) handle(ctx context.Context, tr *mtp.Transfer) {
+	if tr.SI != mtp.ServiceIndicatorSCCP {
+		logf("discarded message with SI=%d from %s", tr.SI, tr.OPC)
+		return
+	}
+
+	m, err := ParseMessageWithVariant(n.Variant, tr.Data)
+	if err != nil {
+		logf("failed to decode message from %s: %s", tr.OPC, err)
+		return
+	}
+
+	u, ok := newUnitdata(m)
+	if !ok {
+		n.handleConnectionOriented(ctx, m, tr.OPC, tr.SLS)
+		return
+	}
+
+	if err := n.transfer(ctx, u, m, tr.OPC, tr.SLS); err != nil {
+		n.returnOnError(ctx, u, tr.OPC, tr.SLS, err)
+	}
+}
+
+// transfer routes the unitdata received from opc, and delivers it locally or sends it
+// to the next node.
+func (n *Node) transfer(ctx context.Context, u *unitdata, m Message, opc params.PointCode, sls uint8) error {
+	cdpa, dpc, translated, err := n.route(u.cdpa, sls)
+	if err != nil {
+		return err
+	}
+
+	if dpc == nil {
+		h := n.handler(cdpa.SubsystemNumber)
+		if cdpa.HasSSN() && cdpa.SubsystemNumber == ssnSCMG && !u.isService() {
+			n.handleSCMG(u.data, opc)
+			if h == nil {
+				return nil
+			}
+		}
+		if h == nil || !cdpa.HasSSN() {
+			return &RoutingError{Cause: params.ReturnCauseUnequippedUser}
+		}
+
+		data := u.data
+		if n.Reassembler != nil && !u.isService() {
+			d, ok, err := n.Reassembler.reassemble(u, withOPC(u.cgpa, opc))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			data = d
+		}
+
+		ind := &Indication{
+			OPC:                 opc,
+			SLS:                 sls,
+			Message:             m,
+			CalledPartyAddress:  cdpa,
+			CallingPartyAddress: u.cgpa,
+			Data:                data,
+		}
+		if u.isService() {
+			ind.ReturnCause = params.NewCause(u.cause)
+		}
+
+		h(ind)
+		return nil
+	}
+
+	if translated && u.typ != MsgTypeUDT && u.typ != MsgTypeUDTS {
+		if u.hc <= 1 {
+			return &RoutingError{Cause: params.ReturnCauseHopCounterViolation}
+		}
+		u.hc--
+	}
+
+	u.cdpa = cdpa
+	if !n.isLocal(opc) {
+		u.cgpa = withOPC(u.cgpa, opc)
+	}
+
+	return n.sendTo(ctx, *dpc, sls, u.message())
+}
+
+// handleSCMG handles the SCMG received from opc, which updates the availability of
+// the affected subsystem in the Translator on SSP and SSA.
+func (n *Node) handleSCMG(b []byte, opc params.PointCode) {
+	m, err := ParseSCMGWithVariant(n.Variant, b)
+	if err != nil {
+		logf("failed to decode SCMG from %s: %s", opc, err)
+		return
+	}
+	if n.Translator == nil {
+		return
+	}
+
+	switch m.Type {
+	case SCMGTypeSSP:
+		n.Translator.SetSubsystemAvailable(m.AffectedPC, m.AffectedSSN, false)
+	case SCMGTypeSSA:
+		n.Translator.SetSubsystemAvailable(m.AffectedPC, m.AffectedSSN, true)
+	}
+}
+
+// route determines the destination of the message by the Called Party Address.
+//
+// It returns the Called Party Address to be used after the routing, and the DPC
+// of the next node, or nil if the destination is the local node.
+func (n *Node) route(cdpa *params.PartyAddress, sls uint8) (*params.PartyAddress, *params.PointCode, bool, error) {
+	var translated bool
+	if cdpa.RouteOnGT() {
+		if n.Translator == nil {
+			return nil, nil, false, &RoutingError{Cause: params.ReturnCauseNoTranslationForAnAddressOfSuchNature}
+		}
+
+		res, err := n.Translator.TranslateWithSLS(cdpa, sls)
+		if err != nil {
+			var terr *gtt.TranslationError
+			if errors.As(err, &terr) {
+				return nil, nil, false, &RoutingError{Cause: terr.Cause}
+			}
+			return nil, nil, false, err
+		}
+
+		cdpa, translated = res.CalledPartyAddress, true
+		if res.DPC != nil && !n.isLocal(*res.DPC) {
+			return cdpa, res.DPC, translated, nil
+		}
+	}
+
+	if cdpa.HasPC() && !n.isLocal(cdpa.SignalingPointCode) {
+		pc := cdpa.SignalingPointCode
+		return cdpa, &pc, translated, nil
+	}
+
+	// translated into the local node but still to be routed on GT.
+	if cdpa.RouteOnGT() {
+		return nil, nil, false, &RoutingError{Cause: params.ReturnCauseNoTranslationForAnAddressOfSuchNature}
+	}
+
+	return cdpa, nil, translated, nil
+}
+
+// returnOnError returns the service message to the originator if it is requested,
+// or discards the message otherwise.
+func (n *Node) returnOnError(ctx context.Context, u *unitdata, opc params.PointCode, sls uint8, err error) {
+	// the service message is built from the first segment.
+	var aerr *ReassemblyError
+	if errors.As(err, &aerr) {
+		if aerr.Service == nil {
+			logf("discarded %s from %s: %s", u.typ, opc, err)
+			return
+		}
+
+		// the OPC is already included in the Called Party Address.
+		s, _ := newUnitdata(aerr.Service)
+		if err := n.transfer(ctx, s, s.message(), n.PointCode, sls); err != nil {
+			logf("failed to return %s to %s: %s", s.typ, opc, err)
+		}
+		return
+	}
+
+	var rerr *RoutingError
+	if !errors.As(err, &rerr) {
+		rerr = &RoutingError{Cause: params.ReturnCauseUnqualified}
+	}
+
+	if u.isService() || !u.retOnErr {
+		logf("discarded %s from %s: %s", u.typ, opc, err)
+		return
+	}
+
+	s := u.service(rerr.Cause)
+	s.cdpa = withOPC(u.cgpa, opc)
+	if err := n.transfer(ctx, s, s.message(), n.PointCode, sls); err != nil {
+		logf("failed to return %s to %s: %s", s.typ, opc, err)
+	}
+}
+
+// isLocal reports whether the point code is the one of the local node.
+func (n *Node) isLocal(pc params.PointCode) bool {
+	return pc.Value == n.PointCode.Value
+}
+
+// withOPC returns the copy of the Calling Party Address with the OPC included if
+// it is routed on SSN without the point code, so that the message can be returned
+// to the originator after relayed. See Q.714 2.7.
+func withOPC(cgpa *params.PartyAddress, opc params.PointCode) *params.PartyAddress {
+	if cgpa.RouteOnGT() || cgpa.HasPC() {
+		return cgpa
+	}
+
+	c := cgpa.Clone()
+	c.SetSignalingPointCode(opc)
+	return c
+}
@@ -0,0 +1,411 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/mtp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/utils"
+)
+
+// loopback is an in-memory MTP network that delivers the messages by DPC.
+type loopback map[uint32]chan *mtp.Transfer
+
+func (l loopback) sap(pc uint32) mtp.SAP {
+	l[pc] = make(chan *mtp.Transfer, 8)
+	return &loopbackSAP{l, pc}
+}
+
+type loopbackSAP struct {
+	network loopback
+	pc      uint32
+}
+
+func (s *loopbackSAP) Send(ctx context.Context, t *mtp.Transfer) error {
+	select {
+	case s.network[t.DPC.Value] <- t:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *loopbackSAP) Receive(ctx context.Context) (mtp.Primitive, error) {
+	select {
+	case t := <-s.network[s.pc]:
+		return t, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestNode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	pc := func(v uint32) params.PointCode {
+		return params.VariantITU.NewPointCode(v)
+	}
+	gtAddr := func(digits string, routeOnSSN bool, ssn uint8) *params.PartyAddress {
+		es := params.ESBCDEven
+		if len(digits)%2 == 1 {
+			es = params.ESBCDOdd
+		}
+		return params.NewCalledPartyAddress(
+			params.NewAddressIndicator(false, ssn != 0, routeOnSSN, params.GTITTNPESNAI), 0, ssn,
+			params.NewGlobalTitle(
+				params.GTITTNPESNAI, 0, params.NPISDNTelephony, es,
+				params.NAIInternationalNumber, utils.MustBCDEncode(digits),
+			),
+		)
+	}
+
+	// A (1) sends to B (3) via STP (2) by GT.
+	network := loopback{}
+	a := sccp.NewNode(params.VariantITU, pc(1), network.sap(1))
+	stp := sccp.NewNode(params.VariantITU, pc(2), network.sap(2))
+	b := sccp.NewNode(params.VariantITU, pc(3), network.sap(3))
+
+	sel := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+	dpcSTP, dpcB := pc(2), pc(3)
+
+	var err error
+	a.Translator, err = gtt.NewTranslator(
+		&gtt.Rule{Selector: sel, Prefix: "", Destinations: []*gtt.Destination{{DPC: &dpcSTP}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stp.Translator, err = gtt.NewTranslator(
+		&gtt.Rule{Selector: sel, Prefix: "8190", Destinations: []*gtt.Destination{{DPC: &dpcB, RouteOnSSN: true}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indA, indB := make(chan *sccp.Indication, 1), make(chan *sccp.Indication, 1)
+	a.Register(8, func(ind *sccp.Indication) { indA <- ind })
+	b.Register(6, func(ind *sccp.Indication) { indB <- ind })
+
+	for _, n := range []*sccp.Node{a, stp, b} {
+		go func(n *sccp.Node) { _ = n.Serve(ctx) }(n)
+	}
+
+	cgpa := params.NewCallingPartyAddress(
+		params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 8, nil,
+	)
+
+	t.Run("relay by GT", func(t *testing.T) {
+		udt := sccp.NewUDT(0, true, gtAddr("81901234", false, 6), cgpa, []byte{0xde, 0xad})
+		if err := a.Send(ctx, udt, 5); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ind := <-indB:
+			verify.Values(t, "OPC", ind.OPC, pc(2))
+			verify.Values(t, "SLS", ind.SLS, uint8(5))
+			verify.Values(t, "CdPA", ind.CalledPartyAddress, gtAddr("81901234", true, 6))
+			verify.Values(t, "Data", ind.Data, []byte{0xde, 0xad})
+			if !ind.CallingPartyAddress.HasPC() || ind.CallingPartyAddress.SignalingPointCode != pc(1) {
+				t.Errorf("OPC is not included in CgPA: %s", ind.CallingPartyAddress)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	})
+
+	t.Run("return on error", func(t *testing.T) {
+		udt := sccp.NewUDT(0, true, gtAddr("44901234", false, 6), cgpa, []byte{0xbe, 0xef})
+		if err := a.Send(ctx, udt, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ind := <-indA:
+			verify.Values(t, "OPC", ind.OPC, pc(2))
+			verify.Values(t, "ReturnCause", ind.ReturnCause.Value(), params.ReturnCauseNoTranslationForThisSpecificAddress)
+			verify.Values(t, "Data", ind.Data, []byte{0xbe, 0xef})
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	})
+
+	t.Run("unequipped user", func(t *testing.T) {
+		cdpa := params.NewCalledPartyAddress(
+			params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 7, nil,
+		)
+		err := a.Send(ctx, sccp.NewUDT(0, false, cdpa, cgpa, []byte{0x00}), 0)
+		rerr, ok := err.(*sccp.RoutingError)
+		if !ok {
+			t.Fatalf("expected RoutingError, got %v", err)
+		}
+		verify.Values(t, "Cause", rerr.Cause, params.ReturnCauseUnequippedUser)
+	})
+}
+
+func TestNodePause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	pcA, pcB, pcC := params.VariantITU.NewPointCode(1), params.VariantITU.NewPointCode(2), params.VariantITU.NewPointCode(3)
+	sapA, peer := mtp.NewPipe()
+	defer sapA.Close()
+
+	a := sccp.NewNode(params.VariantITU, pcA, sapA)
+	sel := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+
+	var err error
+	a.Translator, err = gtt.NewTranslator(&gtt.Rule{
+		Selector: sel, Mode: gtt.ModeDominant,
+		Destinations: []*gtt.Destination{{DPC: &pcB}, {DPC: &pcC}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ind := make(chan *sccp.Indication, 1)
+	a.Register(8, func(i *sccp.Indication) { ind <- i })
+	go func() { _ = a.Serve(ctx) }()
+
+	cgpa := params.NewCallingPartyAddress(
+		params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 8, nil,
+	)
+	cdpa := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, false, false, params.GTITTNPESNAI), 0, 0,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDEven,
+			params.NAIInternationalNumber, utils.MustBCDEncode("8190"),
+		),
+	)
+
+	send := func(t *testing.T) params.PointCode {
+		t.Helper()
+		if err := a.Send(ctx, sccp.NewUDT(0, false, cdpa, cgpa, []byte{0x01}), 0); err != nil {
+			t.Fatal(err)
+		}
+		prim, err := peer.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prim.(*mtp.Transfer).DPC
+	}
+
+	// the Pause is handled before the UDT delivered after it.
+	indicate := func(t *testing.T, prim mtp.Primitive) {
+		t.Helper()
+		if err := sapA.Indicate(ctx, prim); err != nil {
+			t.Fatal(err)
+		}
+
+		udt, err := sccp.NewUDT(0, false, cgpa, cgpa, []byte{0x02}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := peer.Send(ctx, &mtp.Transfer{OPC: pcB, DPC: pcA, SI: mtp.ServiceIndicatorSCCP, Data: udt}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-ind:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	verify.Values(t, "primary", send(t), pcB)
+
+	indicate(t, &mtp.Pause{AffectedPC: pcB})
+	verify.Values(t, "backup", send(t), pcC)
+
+	indicate(t, &mtp.Resume{AffectedPC: pcB})
+	verify.Values(t, "resumed", send(t), pcB)
+}
+
+func TestNodeSCMG(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	pcA, pcB, pcC := params.VariantITU.NewPointCode(1), params.VariantITU.NewPointCode(2), params.VariantITU.NewPointCode(3)
+	sapA, peer := mtp.NewPipe()
+	defer sapA.Close()
+
+	a := sccp.NewNode(params.VariantITU, pcA, sapA)
+	sel := gtt.NewSelector(0, params.NPISDNTelephony, params.NAIInternationalNumber, params.ESBCDEven)
+
+	var err error
+	a.Translator, err = gtt.NewTranslator(&gtt.Rule{
+		Selector: sel, Mode: gtt.ModeDominant,
+		Destinations: []*gtt.Destination{{DPC: &pcB, SSN: 8}, {DPC: &pcC, SSN: 8}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ind := make(chan *sccp.Indication, 1)
+	a.Register(8, func(i *sccp.Indication) { ind <- i })
+	go func() { _ = a.Serve(ctx) }()
+
+	cgpa := params.NewCallingPartyAddress(
+		params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 8, nil,
+	)
+	cdpa := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, false, false, params.GTITTNPESNAI), 0, 0,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDEven,
+			params.NAIInternationalNumber, utils.MustBCDEncode("8190"),
+		),
+	)
+	scmgAddr := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 1, nil,
+	)
+
+	send := func(t *testing.T) params.PointCode {
+		t.Helper()
+		if err := a.Send(ctx, sccp.NewUDT(0, false, cdpa, cgpa, []byte{0x01}), 0); err != nil {
+			t.Fatal(err)
+		}
+		prim, err := peer.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prim.(*mtp.Transfer).DPC
+	}
+
+	// the SCMG is handled before the UDT delivered after it.
+	transfer := func(t *testing.T, m *sccp.SCMG) {
+		t.Helper()
+		scmg, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []*sccp.UDT{
+			sccp.NewUDT(0, false, scmgAddr, scmgAddr, scmg),
+			sccp.NewUDT(0, false, cgpa, cgpa, []byte{0x02}),
+		} {
+			b, err := u.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := peer.Send(ctx, &mtp.Transfer{OPC: pcB, DPC: pcA, SI: mtp.ServiceIndicatorSCCP, Data: b}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		select {
+		case <-ind:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	verify.Values(t, "primary", send(t), pcB)
+
+	transfer(t, sccp.NewSCMG(sccp.SCMGTypeSSP, 8, pcB.Value, 0, 0))
+	verify.Values(t, "backup", send(t), pcC)
+
+	transfer(t, sccp.NewSCMG(sccp.SCMGTypeSSA, 8, pcB.Value, 0, 0))
+	verify.Values(t, "allowed", send(t), pcB)
+}
+
+func TestNodeReassembly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	network := loopback{}
+	a := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(1), network.sap(1))
+	b := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(2), network.sap(2))
+	b.Reassembler = sccp.NewReassembler()
+
+	indA, indB := make(chan *sccp.Indication, 1), make(chan *sccp.Indication, 1)
+	a.Register(6, func(ind *sccp.Indication) { indA <- ind })
+	b.Register(8, func(ind *sccp.Indication) { indB <- ind })
+	for _, n := range []*sccp.Node{a, b} {
+		go func(n *sccp.Node) { _ = n.Serve(ctx) }(n)
+	}
+
+	data := make([]byte, 1000)
+	xudts, err := sccp.NewSegmenter(params.VariantITU, sccp.MaxUserPartLenMTP3).Segment(
+		0, true, 15, ssnAddr(2, 8), ssnAddr(1, 6), data,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reassembled", func(t *testing.T) {
+		for _, x := range xudts {
+			if err := a.Send(ctx, x, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		select {
+		case ind := <-indB:
+			verify.Values(t, "Data", ind.Data, data)
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	})
+
+	t.Run("segmentation failure", func(t *testing.T) {
+		for _, x := range []*sccp.XUDT{xudts[0], xudts[0]} {
+			if err := a.Send(ctx, x, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		select {
+		case ind := <-indA:
+			verify.Values(t, "ReturnCause", ind.ReturnCause.Value(), params.ReturnCauseSegmentationFailure)
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	})
+
+	t.Run("T(reass) expiry", func(t *testing.T) {
+		network := loopback{}
+		c := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(3), network.sap(3))
+		d := sccp.NewNode(params.VariantITU, params.VariantITU.NewPointCode(4), network.sap(4))
+		d.Reassembler = sccp.NewReassembler()
+		d.Reassembler.Timeout = 50 * time.Millisecond
+		d.Reassembler.Expired = func(err *sccp.ReassemblyError) {
+			if err := d.Send(ctx, err.Service, 0); err != nil {
+				t.Error(err)
+			}
+		}
+
+		indC := make(chan *sccp.Indication, 1)
+		c.Register(6, func(ind *sccp.Indication) { indC <- ind })
+		d.Register(8, func(ind *sccp.Indication) { t.Error("unexpected N-UNITDATA indication") })
+		for _, n := range []*sccp.Node{c, d} {
+			go func(n *sccp.Node) { _ = n.Serve(ctx) }(n)
+		}
+
+		// the Calling Party Address without the point code is completed with the OPC.
+		cgpa := params.NewCallingPartyAddress(params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 6, nil)
+		xudts, err := sccp.NewSegmenter(params.VariantITU, sccp.MaxUserPartLenMTP3).Segment(
+			0, true, 15, ssnAddr(4, 8), cgpa, data,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Send(ctx, xudts[0], 0); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ind := <-indC:
+			verify.Values(t, "ReturnCause", ind.ReturnCause.Value(), params.ReturnCauseSegmentationFailure)
+			verify.Values(t, "OPC", ind.OPC.Value, uint32(4))
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	})
+}
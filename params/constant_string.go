@@ -1,4 +1,4 @@
-// Code generated by "stringer -type ParameterNameCode,ParameterType,ReleaseCauseValue,ReturnCauseValue,ResetCauseValue,ErrorCauseValue,RefusalCauseValue,GlobalTitleIndicator,NatureOfAddressIndicator,NumberingPlan,EncodingScheme -linecomment -output constant_string.go"; DO NOT EDIT.
+// Code generated by "stringer -type ParameterNameCode,ParameterType,ReleaseCauseValue,ReturnCauseValue,ResetCauseValue,ErrorCauseValue,RefusalCauseValue,GlobalTitleIndicator,NatureOfAddressIndicator,NumberingPlan,EncodingScheme,Variant -linecomment -output constant_string.go"; DO NOT EDIT.
 
 package params
 
@@ -246,6 +246,8 @@ func _() {
 	_ = x[GTITTOnly-2]
 	_ = x[GTITTNPES-3]
 	_ = x[GTITTNPESNAI-4]
+	_ = x[GTIANSITTNPES-1]
+	_ = x[GTIANSITTOnly-2]
 }
 
 const _GlobalTitleIndicator_name = "no global title includedglobal title includes nature of address indicator onlyglobal title includes translation type onlyglobal title includes translation type, numbering plan, and encoding schemeglobal title includes translation type, numbering plan, encoding scheme, and nature of address indicator"
@@ -343,3 +345,23 @@ func (i EncodingScheme) String() string {
 	}
 	return _EncodingScheme_name[_EncodingScheme_index[i]:_EncodingScheme_index[i+1]]
 }
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[VariantITU-0]
+	_ = x[VariantANSI-1]
+	_ = x[VariantChina-2]
+}
+
+const _Variant_name = "ITU-T Q.713ANSI T1.112China GF 001-9001"
+
+var _Variant_index = [...]uint8{0, 11, 22, 39}
+
+func (i Variant) String() string {
+	if i >= Variant(len(_Variant_index)-1) {
+		return "Variant(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Variant_name[_Variant_index[i]:_Variant_index[i+1]]
+}
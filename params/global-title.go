@@ -12,6 +12,9 @@ type GlobalTitle struct {
 	// GTI is included in the Address Indicator which is not a part of
 	// Global Title itself, but necessary to encode/decode it properly.
 	GTI GlobalTitleIndicator
+	// Variant is the protocol variant that the GlobalTitle is encoded in,
+	// which determines the meaning of GTI.
+	Variant Variant
 	TranslationType
 	NumberingPlan
 	EncodingScheme
@@ -32,6 +35,25 @@ const (
 	GTITTNPESNAI GlobalTitleIndicator = 0b0100 // global title includes translation type, numbering plan, encoding scheme, and nature of address indicator
 )
 
+// GlobalTitleIndicator values in ANSI T1.112.
+// See T1.112.3 3.4.1 for more details.
+const (
+	GTIANSITTNPES GlobalTitleIndicator = 0b0001 // global title includes translation type, numbering plan, and encoding scheme
+	GTIANSITTOnly GlobalTitleIndicator = 0b0010 // global title includes translation type only
+)
+
+// gtFormat is a set of the fields included in a GlobalTitle, which is determined
+// by the combination of GlobalTitleIndicator and Variant.
+type gtFormat uint8
+
+const (
+	gtFormatNone gtFormat = iota
+	gtFormatNAIOnly
+	gtFormatTTOnly
+	gtFormatTTNPES
+	gtFormatTTNPESNAI
+)
+
 // NatureOfAddressIndicator is a type of Nature of Address Indicator.
 type NatureOfAddressIndicator uint8
 
@@ -96,18 +118,34 @@ func NewGlobalTitle(
 	nai NatureOfAddressIndicator,
 	addr []byte,
 ) *GlobalTitle {
-	gt := &GlobalTitle{GTI: gti}
+	return NewGlobalTitleWithVariant(VariantITU, gti, tt, np, es, nai, addr)
+}
 
-	switch gti {
-	case GTINAIOnly:
+// NewGlobalTitleWithVariant creates a new GlobalTitle in the given protocol variant.
+//
+// The values that are not included in the GlobalTitle of the given GTI are ignored,
+// e.g., nai is always ignored in ANSI.
+func NewGlobalTitleWithVariant(
+	v Variant,
+	gti GlobalTitleIndicator,
+	tt TranslationType,
+	np NumberingPlan,
+	es EncodingScheme,
+	nai NatureOfAddressIndicator,
+	addr []byte,
+) *GlobalTitle {
+	gt := &GlobalTitle{GTI: gti, Variant: v}
+
+	switch gt.format() {
+	case gtFormatNAIOnly:
 		gt.NatureOfAddressIndicator = nai
-	case GTITTOnly:
+	case gtFormatTTOnly:
 		gt.TranslationType = tt
-	case GTITTNPES:
+	case gtFormatTTNPES:
 		gt.TranslationType = tt
 		gt.NumberingPlan = np
 		gt.EncodingScheme = es
-	case GTITTNPESNAI:
+	case gtFormatTTNPESNAI:
 		gt.TranslationType = tt
 		gt.NumberingPlan = np
 		gt.EncodingScheme = es
@@ -126,18 +164,18 @@ func (g *GlobalTitle) Write(b []byte) (int, error) {
 	}
 
 	n := 0
-	switch g.GTI {
-	case GTINAIOnly:
+	switch g.format() {
+	case gtFormatNAIOnly:
 		b[n] = uint8(g.NatureOfAddressIndicator)
 		n++
-	case GTITTOnly:
+	case gtFormatTTOnly:
 		b[n] = uint8(g.TranslationType)
 		n++
-	case GTITTNPES:
+	case gtFormatTTNPES:
 		b[n] = uint8(g.TranslationType)
 		b[n+1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)
 		n += 2
-	case GTITTNPESNAI:
+	case gtFormatTTNPESNAI:
 		b[n] = uint8(g.TranslationType)
 		b[n+1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)
 		b[n+2] = uint8(g.NatureOfAddressIndicator)
@@ -170,7 +208,12 @@ func (g *GlobalTitle) MarshalTo(b []byte) error {
 // The given byte sequence should not include the excess bytes for the parent PartyAddress.
 // otherwise, AddressInformation will include them.
 func ParseGlobalTitle(gti GlobalTitleIndicator, b []byte) (*GlobalTitle, error) {
-	g := &GlobalTitle{GTI: gti}
+	return ParseGlobalTitleWithVariant(VariantITU, gti, b)
+}
+
+// ParseGlobalTitleWithVariant decodes given byte sequence as a GlobalTitle in the given protocol variant.
+func ParseGlobalTitleWithVariant(v Variant, gti GlobalTitleIndicator, b []byte) (*GlobalTitle, error) {
+	g := &GlobalTitle{GTI: gti, Variant: v}
 	if err := g.UnmarshalBinary(b); err != nil {
 		return nil, err
 	}
@@ -189,19 +232,19 @@ func (g *GlobalTitle) Read(b []byte) (int, error) {
 	}
 
 	n := 0
-	switch g.GTI {
-	case GTINAIOnly:
+	switch g.format() {
+	case gtFormatNAIOnly:
 		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[n])
 		n++
-	case GTITTOnly:
+	case gtFormatTTOnly:
 		g.TranslationType = TranslationType(b[n])
 		n++
-	case GTITTNPES:
+	case gtFormatTTNPES:
 		g.TranslationType = TranslationType(b[n])
 		g.NumberingPlan = NumberingPlan(b[n+1] >> 4)
 		g.EncodingScheme = EncodingScheme(b[n+1] & 0x0F)
 		n += 2
-	case GTITTNPESNAI:
+	case gtFormatTTNPESNAI:
 		g.TranslationType = TranslationType(b[n])
 		g.NumberingPlan = NumberingPlan(b[n+1] >> 4)
 		g.EncodingScheme = EncodingScheme(b[n+1] & 0x0F)
@@ -222,19 +265,19 @@ func (g *GlobalTitle) UnmarshalBinary(b []byte) error {
 	}
 
 	offset := 0
-	switch g.GTI {
-	case GTINAIOnly:
+	switch g.format() {
+	case gtFormatNAIOnly:
 		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[offset])
 		offset++
-	case GTITTOnly:
+	case gtFormatTTOnly:
 		g.TranslationType = TranslationType(b[offset])
 		offset++
-	case GTITTNPES:
+	case gtFormatTTNPES:
 		g.TranslationType = TranslationType(b[offset])
 		g.NumberingPlan = NumberingPlan(b[offset+1] >> 4)
 		g.EncodingScheme = EncodingScheme(b[offset+1] & 0x0F)
 		offset += 2
-	case GTITTNPESNAI:
+	case gtFormatTTNPESNAI:
 		g.TranslationType = TranslationType(b[offset])
 		g.NumberingPlan = NumberingPlan(b[offset+1] >> 4)
 		g.EncodingScheme = EncodingScheme(b[offset+1] & 0x0F)
@@ -253,14 +296,14 @@ func (g *GlobalTitle) MarshalLen() int {
 
 func (g *GlobalTitle) lenByGTI() int {
 	var l int
-	switch g.GTI {
-	case GTINAIOnly:
+	switch g.format() {
+	case gtFormatNAIOnly:
 		l += 1
-	case GTITTOnly:
+	case gtFormatTTOnly:
 		l += 1
-	case GTITTNPES:
+	case gtFormatTTNPES:
 		l += 2
-	case GTITTNPESNAI:
+	case gtFormatTTNPESNAI:
 		l += 3
 	}
 
@@ -271,8 +314,73 @@ func (g *GlobalTitle) lenByGTI() int {
 	return l
 }
 
+// format returns the set of the fields included in the GlobalTitle.
+func (g *GlobalTitle) format() gtFormat {
+	if g.Variant == VariantANSI {
+		switch g.GTI {
+		case GTIANSITTNPES:
+			return gtFormatTTNPES
+		case GTIANSITTOnly:
+			return gtFormatTTOnly
+		}
+		return gtFormatNone
+	}
+
+	switch g.GTI {
+	case GTINAIOnly:
+		return gtFormatNAIOnly
+	case GTITTOnly:
+		return gtFormatTTOnly
+	case GTITTNPES:
+		return gtFormatTTNPES
+	case GTITTNPESNAI:
+		return gtFormatTTNPESNAI
+	}
+	return gtFormatNone
+}
+
+// SetAddress encodes the given digits in BCD and sets them in AddressInformation.
+//
+// The odd/even indication in EncodingScheme (or in NatureOfAddressIndicator when the
+// GlobalTitle has NAI only) is updated by the number of the digits, if it is in BCD.
+// The length of the parent PartyAddress should be updated with SetLength after this.
+func (g *GlobalTitle) SetAddress(digits string) error {
+	b, err := utils.BCDEncode(digits)
+	if err != nil {
+		return err
+	}
+	g.AddressInformation = b
+
+	odd := len(digits)%2 == 1
+	switch g.format() {
+	case gtFormatNAIOnly:
+		if odd {
+			g.NatureOfAddressIndicator = g.NatureOfAddressIndicator.Odd()
+		} else {
+			g.NatureOfAddressIndicator = g.NatureOfAddressIndicator.Even()
+		}
+	case gtFormatTTNPES, gtFormatTTNPESNAI:
+		if g.EncodingScheme != ESBCDOdd && g.EncodingScheme != ESBCDEven {
+			break
+		}
+		if odd {
+			g.EncodingScheme = ESBCDOdd
+		} else {
+			g.EncodingScheme = ESBCDEven
+		}
+	}
+
+	return nil
+}
+
 // IsOddDigits reports whether AddressInformation is odd number or not.
+//
+// It is indicated by the last bit of NatureOfAddressIndicator if the GlobalTitle
+// has NAI only, and by EncodingScheme otherwise.
 func (g *GlobalTitle) IsOddDigits() bool {
+	if g.format() == gtFormatNAIOnly {
+		return g.NatureOfAddressIndicator&0b10000000 != 0
+	}
 	return g.EncodingScheme == ESBCDOdd
 }
 
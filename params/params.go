@@ -89,10 +89,16 @@ const (
 
 // ParseOptionalParameters parses optional parameters from the given byte sequence.
 func ParseOptionalParameters(b []byte) ([]Parameter, int, error) {
+	return ParseOptionalParametersWithVariant(VariantITU, b)
+}
+
+// ParseOptionalParametersWithVariant parses optional parameters from the given byte sequence
+// in the given protocol variant.
+func ParseOptionalParametersWithVariant(v Variant, b []byte) ([]Parameter, int, error) {
 	var params []Parameter
 	var offset int
 	for len(b) > 0 {
-		p, n, err := ParseOptionalParameter(b[offset:])
+		p, n, err := ParseOptionalParameterWithVariant(v, b[offset:])
 		if err != nil {
 			return nil, offset, err
 		}
@@ -107,6 +113,12 @@ func ParseOptionalParameters(b []byte) ([]Parameter, int, error) {
 
 // ParseOptionalParameter parses a single optional parameter from the given byte sequence.
 func ParseOptionalParameter(b []byte) (Parameter, int, error) {
+	return ParseOptionalParameterWithVariant(VariantITU, b)
+}
+
+// ParseOptionalParameterWithVariant parses a single optional parameter from the given byte sequence
+// in the given protocol variant.
+func ParseOptionalParameterWithVariant(v Variant, b []byte) (Parameter, int, error) {
 	if len(b) < 1 {
 		return nil, 0, io.ErrUnexpectedEOF
 	}
@@ -119,11 +131,13 @@ func ParseOptionalParameter(b []byte) (Parameter, int, error) {
 		p = &PartyAddress{
 			paramType: PTypeO,
 			code:      PCodeCalledPartyAddress,
+			Variant:   v,
 		}
 	case PCodeCallingPartyAddress:
 		p = &PartyAddress{
 			paramType: PTypeO,
 			code:      PCodeCallingPartyAddress,
+			Variant:   v,
 		}
 	case PCodeCredit:
 		p = &Credit{paramType: PTypeO}
@@ -351,8 +365,11 @@ type PartyAddress struct {
 	code      ParameterNameCode
 	length    int
 
+	// Variant is the protocol variant that the PartyAddress is encoded in.
+	// It determines the layout of Indicator and the length of SignalingPointCode.
+	Variant            Variant
 	Indicator          uint8
-	SignalingPointCode uint16
+	SignalingPointCode PointCode
 	SubsystemNumber    uint8
 	*GlobalTitle
 }
@@ -378,6 +395,38 @@ func NewAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicato
 	return ai
 }
 
+// NewAddressIndicatorWithVariant creates a new AddressIndicator in the format used in the
+// given protocol variant, which is meant to be used in NewPartyAddressWithVariant.
+//
+// The last bit is set to 1 (national) only in VariantANSI, and to 0 in the others.
+func NewAddressIndicatorWithVariant(v Variant, hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicator) uint8 {
+	if v != VariantANSI {
+		return NewAddressIndicator(hasPC, hasSSN, routeOnSSN, gti)
+	}
+	return NewANSIAddressIndicator(hasPC, hasSSN, routeOnSSN, gti)
+}
+
+// NewANSIAddressIndicator creates a new AddressIndicator in the ANSI T1.112 format, which is
+// meant to be used in NewPartyAddressWithVariant with VariantANSI.
+//
+// In ANSI, the positions of the PC and SSN indicators are swapped from the ITU-T format.
+// The last bit, which is the national/international indicator, is always set to 1 (national).
+func NewANSIAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicator) uint8 {
+	ai := uint8(0b10000000)
+	if hasSSN {
+		ai |= 0b00000001
+	}
+	if hasPC {
+		ai |= 0b00000010
+	}
+	if routeOnSSN {
+		ai |= 0b01000000
+	}
+	ai |= uint8(gti) << 2
+
+	return ai
+}
+
 // NewPartyAddress creates a new PartyAddress from properly-typed values.
 //
 // The given SPC and SSN are set to 0 if the corresponding bit is not properly set in the
@@ -386,7 +435,16 @@ func NewAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicato
 // When you are aware of the type of PartyAddress you are creating, you can use
 // NewCalled/CallingPartyAddress to create a PartyAddress with the correct code.
 // Otherwise, you can use AsCalled/Calling to set the code after creating a PartyAddress.
-func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+//
+// The PartyAddress is created in ITU-T format. Use NewPartyAddressWithVariant for the others.
+func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
+	return NewPartyAddressWithVariant(VariantITU, cdcg, ai, spc, ssn, gt)
+}
+
+// NewPartyAddressWithVariant creates a new PartyAddress in the given protocol variant.
+//
+// The Variant of the given GlobalTitle is overwritten with v so that it is encoded consistently.
+func NewPartyAddressWithVariant(v Variant, cdcg ParameterNameCode, ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
 	if cdcg != PCodeCalledPartyAddress && cdcg != PCodeCallingPartyAddress {
 		logf("invalid parameter code: expected %v or %v, got %v", PCodeCalledPartyAddress, PCodeCallingPartyAddress, cdcg)
 	}
@@ -394,12 +452,20 @@ func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt
 	p := &PartyAddress{
 		paramType:   PTypeV,
 		code:        cdcg,
+		Variant:     v,
 		Indicator:   ai,
 		GlobalTitle: gt,
 	}
 
+	if gt != nil {
+		gt.Variant = v
+	}
+
 	if p.HasPC() {
-		p.SignalingPointCode = spc
+		p.SignalingPointCode = v.NewPointCode(spc)
+		if err := p.SignalingPointCode.Validate(); err != nil {
+			logf("%s in NewPartyAddress", err)
+		}
 	}
 
 	if p.HasSSN() {
@@ -411,60 +477,91 @@ func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt
 }
 
 // NewPartyAddressOptional creates a new PartyAddress from properly-typed values.
-func NewPartyAddressOptional(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
-	p := NewPartyAddress(cdcg, ai, spc, ssn, gt)
+func NewPartyAddressOptional(cdcg ParameterNameCode, ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
+	return NewPartyAddressOptionalWithVariant(VariantITU, cdcg, ai, spc, ssn, gt)
+}
+
+// NewPartyAddressOptionalWithVariant creates a new PartyAddress as an optional parameter
+// in the given protocol variant.
+func NewPartyAddressOptionalWithVariant(v Variant, cdcg ParameterNameCode, ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
+	p := NewPartyAddressWithVariant(v, cdcg, ai, spc, ssn, gt)
 	p.paramType = PTypeO
 	return p
 }
 
 // NewCalledPartyAddress creates a new PartyAddress for Called Party Address.
-func NewCalledPartyAddress(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCalledPartyAddress(ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
 	return NewPartyAddress(PCodeCalledPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCallingPartyAddress creates a new PartyAddress for Calling Party Address.
-func NewCallingPartyAddress(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCallingPartyAddress(ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
 	return NewPartyAddress(PCodeCallingPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCalledPartyAddressOptional creates a new PartyAddress for Called Party Address as an optional parameter.
-func NewCalledPartyAddressOptional(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCalledPartyAddressOptional(ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
 	return NewPartyAddressOptional(PCodeCalledPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCallingPartyAddressOptional creates a new PartyAddress for Calling Party Address as an optional parameter.
-func NewCallingPartyAddressOptional(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCallingPartyAddressOptional(ai uint8, spc uint32, ssn uint8, gt *GlobalTitle) *PartyAddress {
 	return NewPartyAddressOptional(PCodeCallingPartyAddress, ai, spc, ssn, gt)
 }
 
 // ParseCalledPartyAddress parses the given byte sequence as a mandatory fixed length
 // Called Party Address and returns it as a PartyAddress.
 func ParseCalledPartyAddress(b []byte) (*PartyAddress, int, error) {
-	return parsePartyAddress(PTypeV, PCodeCalledPartyAddress, b)
+	return parsePartyAddress(VariantITU, PTypeV, PCodeCalledPartyAddress, b)
 }
 
 // ParseCallingPartyAddress parses the given byte sequence as a mandatory fixed length
 // Calling Party Address and returns it as a PartyAddress.
 func ParseCallingPartyAddress(b []byte) (*PartyAddress, int, error) {
-	return parsePartyAddress(PTypeV, PCodeCallingPartyAddress, b)
+	return parsePartyAddress(VariantITU, PTypeV, PCodeCallingPartyAddress, b)
 }
 
 // ParseCalledPartyAddressOptional parses the given byte sequence as an optional
 // Called Party Address and returns it as a PartyAddress.
 func ParseCalledPartyAddressOptional(b []byte) (*PartyAddress, int, error) {
-	return parsePartyAddress(PTypeO, PCodeCalledPartyAddress, b)
+	return parsePartyAddress(VariantITU, PTypeO, PCodeCalledPartyAddress, b)
 }
 
 // ParseCallingPartyAddressOptional parses the given byte sequence as an optional
 // Calling Party Address and returns it as a PartyAddress.
 func ParseCallingPartyAddressOptional(b []byte) (*PartyAddress, int, error) {
-	return parsePartyAddress(PTypeO, PCodeCallingPartyAddress, b)
+	return parsePartyAddress(VariantITU, PTypeO, PCodeCallingPartyAddress, b)
+}
+
+// ParseCalledPartyAddressWithVariant parses the given byte sequence as a mandatory
+// Called Party Address in the given protocol variant.
+func ParseCalledPartyAddressWithVariant(v Variant, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddress(v, PTypeV, PCodeCalledPartyAddress, b)
+}
+
+// ParseCallingPartyAddressWithVariant parses the given byte sequence as a mandatory
+// Calling Party Address in the given protocol variant.
+func ParseCallingPartyAddressWithVariant(v Variant, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddress(v, PTypeV, PCodeCallingPartyAddress, b)
+}
+
+// ParseCalledPartyAddressOptionalWithVariant parses the given byte sequence as an optional
+// Called Party Address in the given protocol variant.
+func ParseCalledPartyAddressOptionalWithVariant(v Variant, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddress(v, PTypeO, PCodeCalledPartyAddress, b)
 }
 
-func parsePartyAddress(ptype ParameterType, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+// ParseCallingPartyAddressOptionalWithVariant parses the given byte sequence as an optional
+// Calling Party Address in the given protocol variant.
+func ParseCallingPartyAddressOptionalWithVariant(v Variant, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddress(v, PTypeO, PCodeCallingPartyAddress, b)
+}
+
+func parsePartyAddress(v Variant, ptype ParameterType, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
 	p := &PartyAddress{
 		paramType: ptype,
 		code:      code,
+		Variant:   v,
 	}
 
 	n, err := p.Read(b)
@@ -476,6 +573,8 @@ func parsePartyAddress(ptype ParameterType, code ParameterNameCode, b []byte) (*
 }
 
 // Read sets the values retrieved from byte sequence in a PartyAddress.
+//
+// The byte sequence is decoded in the Variant set in the PartyAddress beforehand.
 func (p *PartyAddress) Read(b []byte) (int, error) {
 	if p.paramType == PTypeO {
 		return p.readOptional(b)
@@ -495,22 +594,15 @@ func (p *PartyAddress) read(b []byte) (int, error) {
 	p.length = int(b[0])
 	p.Indicator = b[1]
 
-	if int(p.length) != len(b)-1 {
+	// the given bytes may contain the subsequent parameters when it's optional.
+	if p.length < 1 || len(b) < p.length+1 {
 		return n, io.ErrUnexpectedEOF
 	}
+	b = b[:p.length+1]
 
-	if p.HasPC() {
-		end := n + 2
-		if end >= len(b) {
-			return n, io.ErrUnexpectedEOF
-		}
-		p.SignalingPointCode = binary.LittleEndian.Uint16(b[n:end])
-		n = end
-	}
-
-	if p.HasSSN() {
-		p.SubsystemNumber = b[n]
-		n++
+	n, err := p.readPCAndSSN(b, n)
+	if err != nil {
+		return n, err
 	}
 
 	gti := p.GTI()
@@ -518,7 +610,7 @@ func (p *PartyAddress) read(b []byte) (int, error) {
 		return n, nil
 	}
 
-	p.GlobalTitle = &GlobalTitle{GTI: gti}
+	p.GlobalTitle = &GlobalTitle{GTI: gti, Variant: p.Variant}
 	m, err := p.GlobalTitle.Read(b[n : int(p.length)+1])
 	if err != nil {
 		return n + m, err
@@ -528,6 +620,37 @@ func (p *PartyAddress) read(b []byte) (int, error) {
 	return n, nil
 }
 
+// readPCAndSSN reads the SPC and SSN from the offset n, in the order defined in the Variant.
+func (p *PartyAddress) readPCAndSSN(b []byte, n int) (int, error) {
+	// In ANSI, SSN comes before SPC.
+	if p.Variant == VariantANSI && p.HasSSN() {
+		if n >= len(b) {
+			return n, io.ErrUnexpectedEOF
+		}
+		p.SubsystemNumber = b[n]
+		n++
+	}
+
+	if p.HasPC() {
+		end := n + p.Variant.PointCodeLen()
+		if end > len(b) {
+			return n, io.ErrUnexpectedEOF
+		}
+		p.SignalingPointCode = p.Variant.readPointCode(b[n:end])
+		n = end
+	}
+
+	if p.Variant != VariantANSI && p.HasSSN() {
+		if n >= len(b) {
+			return n, io.ErrUnexpectedEOF
+		}
+		p.SubsystemNumber = b[n]
+		n++
+	}
+
+	return n, nil
+}
+
 func (p *PartyAddress) readOptional(b []byte) (int, error) {
 	n := 3
 	if len(b) < n {
@@ -542,7 +665,12 @@ func (p *PartyAddress) readOptional(b []byte) (int, error) {
 		)
 	}
 
-	return p.read(b[1:])
+	m, err := p.read(b[1:])
+	if err != nil {
+		return m + 1, err
+	}
+
+	return m + 1, nil
 }
 
 // Write serializes the PartyAddress parameter and returns it as a byte slice.
@@ -554,7 +682,7 @@ func (p *PartyAddress) Write(b []byte) (int, error) {
 }
 
 func (p *PartyAddress) write(b []byte) (int, error) {
-	if len(b) < p.MarshalLen() {
+	if len(b) < p.marshalLenV() {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -562,12 +690,18 @@ func (p *PartyAddress) write(b []byte) (int, error) {
 	b[1] = p.Indicator
 
 	var n = 2
+	// In ANSI, SSN comes before SPC.
+	if p.Variant == VariantANSI && p.HasSSN() {
+		b[n] = p.SubsystemNumber
+		n++
+	}
+
 	if p.HasPC() {
-		binary.LittleEndian.PutUint16(b[n:n+2], p.SignalingPointCode)
-		n += 2
+		p.Variant.writePointCode(b[n:], p.SignalingPointCode)
+		n += p.Variant.PointCodeLen()
 	}
 
-	if p.HasSSN() {
+	if p.Variant != VariantANSI && p.HasSSN() {
 		b[n] = p.SubsystemNumber
 		n++
 	}
@@ -599,9 +733,17 @@ func (p *PartyAddress) writeOptional(b []byte) (int, error) {
 
 // MarshalLen returns the serial length.
 func (p *PartyAddress) MarshalLen() int {
+	if p.paramType == PTypeO {
+		return 1 + p.marshalLenV()
+	}
+	return p.marshalLenV()
+}
+
+// marshalLenV returns the serial length without the parameter name.
+func (p *PartyAddress) marshalLenV() int {
 	l := 2
 	if p.HasPC() {
-		l += 2
+		l += p.Variant.PointCodeLen()
 	}
 
 	if p.HasSSN() {
@@ -627,8 +769,8 @@ func (p *PartyAddress) Value() *PartyAddress {
 
 // String returns the PartyAddress values in human readable format.
 func (p *PartyAddress) String() string {
-	return fmt.Sprintf("{%s (%s): {length: %d, Indicator: %#08b, SignalingPointCode: %d, SubsystemNumber: %d, GlobalTitle: %v}}",
-		p.code, p.paramType, p.length, p.Indicator, p.SignalingPointCode, p.SubsystemNumber, p.GlobalTitle,
+	return fmt.Sprintf("{%s (%s): {length: %d, Variant: %s, Indicator: %#08b, SignalingPointCode: %s, SubsystemNumber: %d, GlobalTitle: %v}}",
+		p.code, p.paramType, p.length, p.Variant, p.Indicator, p.SignalingPointCode, p.SubsystemNumber, p.GlobalTitle,
 	)
 }
 
@@ -653,18 +795,109 @@ func gti(ai int) GlobalTitleIndicator {
 
 // HasSSN reports whether PartyAddress has a Subsystem Number.
 func (p *PartyAddress) HasSSN() bool {
+	if p.Variant == VariantANSI {
+		return (int(p.Indicator) & 0b1) == 1
+	}
 	return (int(p.Indicator) >> 1 & 0b1) == 1
 }
 
 // HasPC reports whether PartyAddress has a Signaling Point Code.
 func (p *PartyAddress) HasPC() bool {
+	if p.Variant == VariantANSI {
+		return (int(p.Indicator) >> 1 & 0b1) == 1
+	}
 	return (int(p.Indicator) & 0b1) == 1
 }
 
 // SetLength sets the length in length field.
 // This should be called after changing the values in PartyAddress.
 func (p *PartyAddress) SetLength() {
-	p.length = p.MarshalLen() - 1
+	p.length = p.marshalLenV() - 1
+}
+
+// SetRouteOnSSN sets the Routing Indicator in Indicator to route on SSN if true,
+// or on Global Title if false.
+func (p *PartyAddress) SetRouteOnSSN(routeOnSSN bool) {
+	if routeOnSSN {
+		p.Indicator |= 0b01000000
+		return
+	}
+	p.Indicator &^= 0b01000000
+}
+
+// SetSubsystemNumber sets the Subsystem Number and the corresponding bit in Indicator.
+// Giving 0 removes the Subsystem Number from the PartyAddress.
+//
+// The length is updated accordingly.
+func (p *PartyAddress) SetSubsystemNumber(ssn uint8) {
+	p.SubsystemNumber = ssn
+	if ssn == 0 {
+		p.Indicator &^= p.ssnBit()
+	} else {
+		p.Indicator |= p.ssnBit()
+	}
+	p.SetLength()
+}
+
+// SetSignalingPointCode sets the Signaling Point Code and the corresponding bit in Indicator.
+//
+// The length is updated accordingly.
+func (p *PartyAddress) SetSignalingPointCode(pc PointCode) {
+	p.SignalingPointCode = pc
+	p.Indicator |= p.pcBit()
+	p.SetLength()
+}
+
+// RemoveSignalingPointCode removes the Signaling Point Code and the corresponding bit in Indicator.
+//
+// The length is updated accordingly.
+func (p *PartyAddress) RemoveSignalingPointCode() {
+	p.SignalingPointCode = PointCode{}
+	p.Indicator &^= p.pcBit()
+	p.SetLength()
+}
+
+// SetGlobalTitle sets the GlobalTitle and the GTI in Indicator. Giving nil removes
+// the GlobalTitle from the PartyAddress.
+//
+// The Variant of the given GlobalTitle is overwritten with the one of the PartyAddress,
+// and the length is updated accordingly.
+func (p *PartyAddress) SetGlobalTitle(gt *GlobalTitle) {
+	p.Indicator &^= 0b00111100
+	if gt != nil {
+		gt.Variant = p.Variant
+		p.Indicator |= uint8(gt.GTI) << 2
+	}
+	p.GlobalTitle = gt
+	p.SetLength()
+}
+
+func (p *PartyAddress) pcBit() uint8 {
+	if p.Variant == VariantANSI {
+		return 0b00000010
+	}
+	return 0b00000001
+}
+
+func (p *PartyAddress) ssnBit() uint8 {
+	if p.Variant == VariantANSI {
+		return 0b00000001
+	}
+	return 0b00000010
+}
+
+// Clone returns a deep copy of the PartyAddress, which can be modified without
+// affecting the original one.
+func (p *PartyAddress) Clone() *PartyAddress {
+	c := *p
+	if p.GlobalTitle != nil {
+		gt := *p.GlobalTitle
+		if p.GlobalTitle.AddressInformation != nil {
+			gt.AddressInformation = append([]byte{}, p.GlobalTitle.AddressInformation...)
+		}
+		c.GlobalTitle = &gt
+	}
+	return &c
 }
 
 // ProtocolClass is a Protocol Class SCCP parameter.
@@ -929,6 +1162,10 @@ type SequencingSegmenting struct {
 }
 
 // NewSequencingSegmenting creates a new SequencingSegmenting.
+//
+// Deprecated: The values are masked out to 0b01111111 and set in the octets as they are,
+// which does not match the values retrieved by Read. Use NewSequencingSegmentingFromSequence
+// to give P(S) and P(R) instead.
 func NewSequencingSegmenting(snd, rcv uint8, moreData bool) *SequencingSegmenting {
 	return &SequencingSegmenting{
 		paramType:             PTypeF,
@@ -940,6 +1177,21 @@ func NewSequencingSegmenting(snd, rcv uint8, moreData bool) *SequencingSegmentin
 	}
 }
 
+// NewSequencingSegmentingFromSequence creates a new SequencingSegmenting from
+// P(S) and P(R) in 0-127, which are shifted to the left by 1 as they are in the octets.
+// SendSequenceNumber and ReceiveSequenceNumber hold the shifted values, in the same way
+// as the ones retrieved by Read.
+func NewSequencingSegmentingFromSequence(ps, pr uint8, moreData bool) *SequencingSegmenting {
+	return &SequencingSegmenting{
+		paramType:             PTypeF,
+		code:                  PCodeSequencingSegmenting,
+		length:                2,
+		SendSequenceNumber:    ps << 1,
+		ReceiveSequenceNumber: pr << 1,
+		MoreData:              moreData,
+	}
+}
+
 // ParseSequencingSegmenting parses the given byte sequence as a SequencingSegmenting.
 func ParseSequencingSegmenting(b []byte) (*SequencingSegmenting, int, error) {
 	s := &SequencingSegmenting{}
@@ -1081,7 +1333,7 @@ func (c *Credit) read(b []byte) (int, error) {
 func (c *Credit) readOptional(b []byte) (int, error) {
 	n := 3
 	if len(b) < n {
-		return 0, nil
+		return 0, io.ErrUnexpectedEOF
 	}
 
 	c.code = ParameterNameCode(b[0])
@@ -1117,7 +1369,8 @@ func (c *Credit) write(b []byte) (int, error) {
 }
 
 func (c *Credit) writeOptional(b []byte) (int, error) {
-	if len(b) < c.length {
+	n := c.MarshalLen()
+	if len(b) < n {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -1125,7 +1378,7 @@ func (c *Credit) writeOptional(b []byte) (int, error) {
 	b[1] = uint8(c.length)
 	b[2] = c.value
 
-	return c.length, nil
+	return n, nil
 }
 
 // MarshalLen returns the serial length of Credit.
@@ -1500,11 +1753,13 @@ func (d *Data) read(b []byte) (int, error) {
 
 	d.value = b[1 : d.length+1]
 
-	return n, nil
+	return d.length + 1, nil
 }
 
 func (d *Data) readOptional(b []byte) (int, error) {
-	n := len(b)
+	if len(b) < 1 {
+		return 0, io.ErrUnexpectedEOF
+	}
 
 	d.code = ParameterNameCode(b[0])
 	if d.code != PCodeData {
@@ -1512,12 +1767,11 @@ func (d *Data) readOptional(b []byte) (int, error) {
 	}
 
 	m, err := d.read(b[1:])
-	n += m
 	if err != nil {
-		return n, err
+		return m + 1, err
 	}
 
-	return n, nil
+	return m + 1, nil
 }
 
 // Write serializes the Data parameter and returns it as a byte slice.
@@ -1540,18 +1794,18 @@ func (d *Data) write(b []byte) (int, error) {
 	}
 
 	copy(b[1:d.length+1], d.value)
-	return d.length, nil
+	return d.length + 1, nil
 }
 
 func (d *Data) writeOptional(b []byte) (int, error) {
-	if len(b) < d.length {
+	if len(b) < d.length+2 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
 	b[0] = uint8(d.code)
 	b[1] = uint8(d.length)
-	copy(b[2:], d.value)
-	return d.length, nil
+	copy(b[2:d.length+2], d.value)
+	return d.length + 2, nil
 }
 
 // MarshalLen returns the serial length of Data.
@@ -1670,7 +1924,7 @@ func (s *Segmentation) Write(b []byte) (int, error) {
 	}
 
 	b[2] |= s.Class & 0b1 << 6
-	b[2] |= s.RemainingSegments & 0b111
+	b[2] |= s.RemainingSegments & 0b1111
 
 	copy(b[3:], utils.Uint32To24(s.LocalReference))
 
@@ -1808,7 +2062,8 @@ func (h *HopCounter) write(b []byte) (int, error) {
 }
 
 func (h *HopCounter) writeOptional(b []byte) (int, error) {
-	if len(b) < h.length {
+	n := h.MarshalLen()
+	if len(b) < n {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -1816,7 +2071,7 @@ func (h *HopCounter) writeOptional(b []byte) (int, error) {
 	b[1] = uint8(h.length)
 	b[2] = h.value
 
-	return h.length, nil
+	return n, nil
 }
 
 // MarshalLen returns the serial length of HopCounter.
@@ -1975,13 +2230,19 @@ func ParseLongData(b []byte) (*LongData, int, error) {
 }
 
 // Read sets the values retrieved from byte sequence in a LongData.
+//
+// The length indicator of LongData is two octets long, and the least significant
+// octet comes first as well as the other multi-octet fields in SCCP.
 func (l *LongData) Read(b []byte) (int, error) {
 	n := len(b)
+	if n < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
 
 	l.paramType = PTypeV
 	l.code = PCodeLongData
 
-	l.length = int(binary.BigEndian.Uint16(b[:2]))
+	l.length = int(binary.LittleEndian.Uint16(b[:2]))
 	if n < l.length+2 {
 		return n, io.ErrUnexpectedEOF
 	}
@@ -1996,9 +2257,9 @@ func (l *LongData) Write(b []byte) (int, error) {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	binary.BigEndian.PutUint16(b, uint16(l.length))
+	binary.LittleEndian.PutUint16(b, uint16(l.length))
 	copy(b[2:], l.value)
-	return l.length, nil
+	return l.length + 2, nil
 }
 
 // MarshalLen returns the serial length of LongData.
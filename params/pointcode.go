@@ -0,0 +1,154 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PointCodeWidth is a width of Signaling Point Code in bits.
+type PointCodeWidth uint8
+
+// PointCodeWidth values.
+const (
+	PointCodeWidth14 PointCodeWidth = 14 // ITU-T
+	PointCodeWidth16 PointCodeWidth = 16 // Japan
+	PointCodeWidth24 PointCodeWidth = 24 // ANSI, China
+)
+
+// PointCodeNotation is a notation of Signaling Point Code in string.
+type PointCodeNotation uint8
+
+// PointCodeNotation values.
+const (
+	// PointCodeNotationDefault is the notation commonly used for the width,
+	// i.e., 3-8-3 for 14 bits and 8-8-8 for 24 bits, or decimal for 16 bits.
+	PointCodeNotationDefault PointCodeNotation = iota
+	// PointCodeNotationDecimal is a plain decimal notation, e.g., "1234".
+	PointCodeNotationDecimal
+	// PointCodeNotation383 is the 3-8-3 notation for 14-bit ITU-T point codes, e.g., "0-154-2".
+	PointCodeNotation383
+	// PointCodeNotation888 is the 8-8-8 notation for 24-bit ANSI/China point codes
+	// (Network, Cluster and Member), e.g., "10-11-12".
+	PointCodeNotation888
+)
+
+// PointCode is a Signaling Point Code that knows its width.
+type PointCode struct {
+	Value uint32
+	Width PointCodeWidth
+}
+
+// NewPointCode creates a new PointCode, validating that the value fits in the width.
+func NewPointCode(v uint32, w PointCodeWidth) (PointCode, error) {
+	pc := PointCode{Value: v, Width: w}
+	if err := pc.Validate(); err != nil {
+		return PointCode{}, err
+	}
+
+	return pc, nil
+}
+
+// ParsePointCode parses the given string as a PointCode of the given width.
+//
+// The string can be either in decimal or in the structured notation of the width,
+// i.e., 3-8-3 for 14 bits and 8-8-8 for 24 bits. 16 bits can only be in decimal.
+func ParsePointCode(s string, w PointCodeWidth) (PointCode, error) {
+	if !strings.Contains(s, "-") {
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return PointCode{}, fmt.Errorf("invalid point code %q: %w", s, err)
+		}
+		return NewPointCode(uint32(v), w)
+	}
+
+	bits := w.structure()
+	if bits == nil {
+		return PointCode{}, fmt.Errorf("invalid point code %q: unsupported width %d", s, w)
+	}
+
+	parts := strings.Split(s, "-")
+	if len(parts) != len(bits) {
+		return PointCode{}, fmt.Errorf("invalid point code %q: must have %d parts for %d bits", s, len(bits), w)
+	}
+
+	var v uint32
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, int(bits[i]))
+		if err != nil {
+			return PointCode{}, fmt.Errorf("invalid point code %q: %w", s, err)
+		}
+		v = v<<bits[i] | uint32(n)
+	}
+
+	return NewPointCode(v, w)
+}
+
+// Validate checks if the PointCode has a valid width and the value fits in it.
+func (pc PointCode) Validate() error {
+	switch pc.Width {
+	case PointCodeWidth14, PointCodeWidth16, PointCodeWidth24:
+	default:
+		return fmt.Errorf("invalid point code width: %d", pc.Width)
+	}
+
+	if pc.Value >= 1<<pc.Width {
+		return fmt.Errorf("invalid point code %d: exceeds %d bits", pc.Value, pc.Width)
+	}
+
+	return nil
+}
+
+// Format returns the PointCode in string in the given notation.
+//
+// If the notation does not match the width, it falls back to decimal.
+func (pc PointCode) Format(n PointCodeNotation) string {
+	bits := pc.Width.structure()
+	switch n {
+	case PointCodeNotationDefault:
+	case PointCodeNotation383:
+		if pc.Width != PointCodeWidth14 {
+			bits = nil
+		}
+	case PointCodeNotation888:
+		if pc.Width != PointCodeWidth24 {
+			bits = nil
+		}
+	default:
+		bits = nil
+	}
+
+	if bits == nil {
+		return strconv.FormatUint(uint64(pc.Value), 10)
+	}
+
+	parts := make([]string, len(bits))
+	shift := uint8(pc.Width)
+	for i, b := range bits {
+		shift -= b
+		parts[i] = strconv.FormatUint(uint64(pc.Value>>shift&(1<<b-1)), 10)
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// String returns the PointCode in the notation commonly used for the width.
+func (pc PointCode) String() string {
+	return pc.Format(PointCodeNotationDefault)
+}
+
+// structure returns the widths of the parts in the structured notation, from the MSB.
+func (w PointCodeWidth) structure() []uint8 {
+	switch w {
+	case PointCodeWidth14:
+		return []uint8{3, 8, 3}
+	case PointCodeWidth24:
+		return []uint8{8, 8, 8}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+// Variant is a type of protocol variant of SCCP.
+//
+// The variant affects the encoding of some parameters, e.g., the length of the
+// Signaling Point Code and the layout of the Address Indicator in PartyAddress,
+// and the meaning of the Global Title Indicator.
+//
+// VariantChina is the same as VariantITU except that the Signaling Point Code is 24 bits.
+type Variant uint8
+
+// Variant values.
+const (
+	VariantITU   Variant = iota // ITU-T Q.713
+	VariantANSI                 // ANSI T1.112
+	VariantChina                // China GF 001-9001
+)
+
+// PointCodeWidth returns the width of the Signaling Point Code in bits.
+func (v Variant) PointCodeWidth() PointCodeWidth {
+	switch v {
+	case VariantANSI, VariantChina:
+		return PointCodeWidth24
+	default:
+		return PointCodeWidth14
+	}
+}
+
+// NewPointCode creates a new PointCode of the width used in the Variant.
+//
+// Unlike the package-level NewPointCode, it does not validate the value.
+func (v Variant) NewPointCode(pc uint32) PointCode {
+	return PointCode{Value: pc, Width: v.PointCodeWidth()}
+}
+
+// PointCodeLen returns the length of the Signaling Point Code in octets.
+func (v Variant) PointCodeLen() int {
+	switch v {
+	case VariantANSI, VariantChina:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// readPointCode reads the Signaling Point Code from the given byte sequence.
+//
+// The Signaling Point Code is always encoded in little endian, i.e., the least
+// significant octet (or the member octet in ANSI) comes first.
+func (v Variant) readPointCode(b []byte) PointCode {
+	pc := PointCode{Width: v.PointCodeWidth()}
+	for i := 0; i < v.PointCodeLen(); i++ {
+		pc.Value |= uint32(b[i]) << (8 * i)
+	}
+
+	return pc
+}
+
+// writePointCode writes the Signaling Point Code to the given byte sequence.
+func (v Variant) writePointCode(b []byte, pc PointCode) {
+	for i := 0; i < v.PointCodeLen(); i++ {
+		b[i] = uint8(pc.Value >> (8 * i))
+	}
+}
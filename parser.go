@@ -0,0 +1,32 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import "github.com/wmnsk/go-sccp/params"
+
+// Parser decodes the byte sequence into SCCP messages in the configured protocol variant.
+//
+// A Parser is meant to be created per signaling relation (e.g., M3UA association) whose
+// variant is known beforehand, so that the caller does not have to pass the variant to
+// every call. The zero value decodes in ITU-T format, and it is safe for concurrent use.
+type Parser struct {
+	Variant params.Variant
+}
+
+// NewParser creates a new Parser that decodes in the given protocol variant.
+func NewParser(v params.Variant) *Parser {
+	return &Parser{Variant: v}
+}
+
+// ParseMessage decodes the byte sequence into Message by Message Type.
+func (p *Parser) ParseMessage(b []byte) (Message, error) {
+	return ParseMessageWithVariant(p.Variant, b)
+}
+
+// ParseSCMG decodes the byte sequence as a SCMG, which is typically carried in
+// the Data of a UDT/XUDT addressed to SSN=1.
+func (p *Parser) ParseSCMG(b []byte) (*SCMG, error) {
+	return ParseSCMGWithVariant(p.Variant, b)
+}
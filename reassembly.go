@@ -0,0 +1,263 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Default values of the Reassembler.
+const (
+	DefaultReassemblyTimeout = 10 * time.Second
+	DefaultMaxReassemblies   = 1024
+	DefaultMaxReassemblyLen  = 4 << 20
+)
+
+// ReassemblyError indicates the reassembly of the segmented message failed.
+type ReassemblyError struct {
+	Cause  params.ReturnCauseValue
+	Reason string
+	// Service is the XUDTS or LUDTS to be returned to the originator, which is nil if
+	// the return option is not set in the first segment or it is not received.
+	Service Message
+}
+
+// Error returns the type of receiver and the reason of the failure.
+func (e *ReassemblyError) Error() string {
+	return fmt.Sprintf("sccp: failed to reassemble: %s", e.Reason)
+}
+
+// Reassembler reassembles the user data segmented in the XUDTs or LUDTs. See Q.714 4.1.1.3.
+//
+// The segments are identified by the Calling Party Address and the local reference in
+// the Segmentation, and can be given in any order. To protect the node from the segments
+// that are never completed, the reassembly fails when T(reass) expires, and the number of
+// the reassemblies in progress and the octets held in them are limited.
+//
+// The zero value is ready to use with the default values.
+type Reassembler struct {
+	// Timeout is T(reass), the time to wait for all the segments after the first one
+	// in arrival order. DefaultReassemblyTimeout is used if zero.
+	Timeout time.Duration
+	// MaxReassemblies is the maximum number of the reassemblies in progress.
+	// DefaultMaxReassemblies is used if zero.
+	MaxReassemblies int
+	// MaxLen is the maximum total length of the data held in the reassemblies in progress.
+	// DefaultMaxReassemblyLen is used if zero.
+	MaxLen int
+	// Expired is called with the error when T(reass) expires, if not nil.
+	// It is called in its own goroutine.
+	Expired func(err *ReassemblyError)
+
+	mu      sync.Mutex
+	pending map[reassemblyKey]*reassembly
+	len     int
+}
+
+type reassemblyKey struct {
+	cgpa string
+	lrn  uint32
+}
+
+// reassembly is a reassembly in progress.
+type reassembly struct {
+	// cgpa is the Calling Party Address the segments are identified with, which is
+	// used as the Called Party Address of the service message.
+	cgpa  *params.PartyAddress
+	first *unitdata
+	// total is the number of the segments, which is known after the first segment.
+	total int
+	// segs are the data of the segments by the number of the remaining segments.
+	segs  map[uint8][]byte
+	len   int
+	timer *time.Timer
+}
+
+// NewReassembler creates a new Reassembler with the default values.
+func NewReassembler() *Reassembler {
+	return &Reassembler{
+		Timeout:         DefaultReassemblyTimeout,
+		MaxReassemblies: DefaultMaxReassemblies,
+		MaxLen:          DefaultMaxReassemblyLen,
+	}
+}
+
+// Reassemble gives the XUDT or LUDT to the Reassembler, and returns the reassembled data
+// with true when the message is the last one to complete the data.
+//
+// It returns false without error while waiting for the other segments. The message that
+// is not segmented is returned as it is. If it fails, the error is *ReassemblyError and
+// the segments received so far are discarded.
+func (r *Reassembler) Reassemble(m Message) ([]byte, bool, error) {
+	u, ok := newUnitdata(m)
+	if !ok || (u.typ != MsgTypeXUDT && u.typ != MsgTypeLUDT) {
+		return nil, false, UnsupportedTypeError(m.MessageType())
+	}
+
+	return r.reassemble(u, u.cgpa)
+}
+
+// reassemble reassembles the unitdata identified with the Calling Party Address given.
+func (r *Reassembler) reassemble(u *unitdata, cgpa *params.PartyAddress) ([]byte, bool, error) {
+	seg := segmentationOf(u)
+	if seg == nil {
+		return u.data, true, nil
+	}
+
+	key, err := newReassemblyKey(cgpa, seg.LocalReference)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pending == nil {
+		r.pending = map[reassemblyKey]*reassembly{}
+	}
+
+	ra, ok := r.pending[key]
+	if !ok {
+		if len(r.pending) >= orDefault(r.MaxReassemblies, DefaultMaxReassemblies) {
+			return nil, false, reassemblyError(u, seg, cgpa, "too many reassemblies in progress")
+		}
+
+		ra = &reassembly{cgpa: cgpa, segs: map[uint8][]byte{}}
+		r.pending[key] = ra
+		ra.timer = time.AfterFunc(orDefault(r.Timeout, DefaultReassemblyTimeout), func() { r.expire(key, ra) })
+	}
+
+	if reason := ra.add(u, seg); reason != "" {
+		return nil, false, r.fail(key, ra, u, seg, reason)
+	}
+	if r.len+len(u.data) > orDefault(r.MaxLen, DefaultMaxReassemblyLen) {
+		return nil, false, r.fail(key, ra, u, seg, "too long data in reassemblies in progress")
+	}
+	ra.segs[seg.RemainingSegments] = u.data
+	ra.len += len(u.data)
+	r.len += len(u.data)
+
+	if ra.total == 0 || len(ra.segs) < ra.total {
+		return nil, false, nil
+	}
+
+	r.remove(key, ra)
+	data := make([]byte, 0, ra.len)
+	for i := ra.total - 1; i >= 0; i-- {
+		data = append(data, ra.segs[uint8(i)]...)
+	}
+	return data, true, nil
+}
+
+// add checks if the segment is consistent with the ones received so far, and returns
+// the reason if not.
+func (ra *reassembly) add(u *unitdata, seg *params.Segmentation) string {
+	if _, ok := ra.segs[seg.RemainingSegments]; ok {
+		return fmt.Sprintf("duplicated segment with %d remaining", seg.RemainingSegments)
+	}
+
+	if seg.FirstSegment {
+		// the segments received before the first one must be the subsequent ones.
+		for rem := range ra.segs {
+			if rem >= seg.RemainingSegments {
+				return fmt.Sprintf("unexpected segment with %d remaining", rem)
+			}
+		}
+		ra.first = u
+		ra.total = int(seg.RemainingSegments) + 1
+		return ""
+	}
+
+	if ra.total != 0 && int(seg.RemainingSegments) >= ra.total-1 {
+		return fmt.Sprintf("unexpected segment with %d remaining", seg.RemainingSegments)
+	}
+	return ""
+}
+
+// fail discards the reassembly, and returns the error.
+// r.mu must be held by the caller.
+func (r *Reassembler) fail(key reassemblyKey, ra *reassembly, u *unitdata, seg *params.Segmentation, reason string) error {
+	r.remove(key, ra)
+	if ra.first != nil {
+		u, seg = ra.first, segmentationOf(ra.first)
+	}
+	return reassemblyError(u, seg, ra.cgpa, reason)
+}
+
+// expire discards the reassembly on T(reass) expiry.
+func (r *Reassembler) expire(key reassemblyKey, ra *reassembly) {
+	r.mu.Lock()
+	if r.pending[key] != ra {
+		// completed or failed while waiting for the lock.
+		r.mu.Unlock()
+		return
+	}
+	r.remove(key, ra)
+	r.mu.Unlock()
+
+	err := &ReassemblyError{Cause: params.ReturnCauseSegmentationFailure, Reason: "T(reass) expired"}
+	if ra.first != nil {
+		err = reassemblyError(ra.first, segmentationOf(ra.first), ra.cgpa, err.Reason)
+	}
+	if r.Expired != nil {
+		r.Expired(err)
+	}
+}
+
+// remove removes the reassembly.
+// r.mu must be held by the caller.
+func (r *Reassembler) remove(key reassemblyKey, ra *reassembly) {
+	ra.timer.Stop()
+	delete(r.pending, key)
+	r.len -= ra.len
+}
+
+// reassemblyError returns the error with the service message built from the segment
+// if it is the first one with the return option, which is sent to the Calling Party
+// Address given.
+func reassemblyError(u *unitdata, seg *params.Segmentation, cgpa *params.PartyAddress, reason string) *ReassemblyError {
+	err := &ReassemblyError{Cause: params.ReturnCauseSegmentationFailure, Reason: reason}
+	if seg.FirstSegment && u.retOnErr {
+		s := u.service(err.Cause)
+		s.cdpa = cgpa
+		err.Service = s.message()
+	}
+	return err
+}
+
+// orDefault returns v, or the default value d if v is zero.
+func orDefault[T comparable](v, d T) T {
+	var zero T
+	if v == zero {
+		return d
+	}
+	return v
+}
+
+// segmentationOf returns the Segmentation of the unitdata, or nil if not segmented.
+func segmentationOf(u *unitdata) *params.Segmentation {
+	for _, opt := range u.opts {
+		if seg, ok := opt.(*params.Segmentation); ok {
+			return seg
+		}
+	}
+	return nil
+}
+
+func newReassemblyKey(cgpa *params.PartyAddress, lrn uint32) (reassemblyKey, error) {
+	if cgpa == nil {
+		return reassemblyKey{lrn: lrn}, nil
+	}
+
+	b := make([]byte, cgpa.MarshalLen())
+	if _, err := cgpa.Write(b); err != nil {
+		return reassemblyKey{}, err
+	}
+	return reassemblyKey{cgpa: string(b), lrn: lrn}, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestReassembler(t *testing.T) {
+	cdpa, cgpa := ssnAddr(2, 8), ssnAddr(1, 6)
+	data := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 250)
+
+	segment := func(t *testing.T) []*sccp.XUDT {
+		t.Helper()
+		xudts, err := sccp.NewSegmenter(params.VariantITU, sccp.MaxUserPartLenMTP3).Segment(0, true, 15, cdpa, cgpa, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xudts) < 3 {
+			t.Fatalf("too few segments: %d", len(xudts))
+		}
+		return xudts
+	}
+	reassemblyError := func(t *testing.T, err error) *sccp.ReassemblyError {
+		t.Helper()
+		var rerr *sccp.ReassemblyError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("expected ReassemblyError, got %v", err)
+		}
+		verify.Values(t, "Cause", rerr.Cause, params.ReturnCauseSegmentationFailure)
+		return rerr
+	}
+
+	t.Run("any order", func(t *testing.T) {
+		r := sccp.NewReassembler()
+		xudts := segment(t)
+
+		// the last one first, then the first one and the rest.
+		order := append([]*sccp.XUDT{xudts[len(xudts)-1], xudts[0]}, xudts[1:len(xudts)-1]...)
+		for i, x := range order {
+			got, ok, err := r.Reassemble(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i < len(order)-1 {
+				verify.Values(t, "complete", ok, false)
+				continue
+			}
+			verify.Values(t, "complete", ok, true)
+			verify.Values(t, "data", got, data)
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var r sccp.Reassembler
+		xudts := segment(t)
+		for i, x := range xudts {
+			got, ok, err := r.Reassemble(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i < len(xudts)-1 {
+				verify.Values(t, "complete", ok, false)
+				continue
+			}
+			verify.Values(t, "complete", ok, true)
+			verify.Values(t, "data", got, data)
+		}
+	})
+
+	t.Run("duplicated", func(t *testing.T) {
+		r := sccp.NewReassembler()
+		xudts := segment(t)
+
+		for _, x := range []*sccp.XUDT{xudts[0], xudts[1]} {
+			if _, _, err := r.Reassemble(x); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, _, err := r.Reassemble(xudts[1])
+		rerr := reassemblyError(t, err)
+
+		xudts2, ok := rerr.Service.(*sccp.XUDTS)
+		if !ok {
+			t.Fatalf("expected XUDTS, got %v", rerr.Service)
+		}
+		verify.Values(t, "XUDTS CdPA", xudts2.CalledPartyAddress, cgpa)
+		verify.Values(t, "XUDTS Data", xudts2.Data.Value(), xudts[0].Data.Value())
+
+		// the reassembly has been discarded.
+		if _, ok, err := r.Reassemble(xudts[1]); ok || err != nil {
+			t.Errorf("unexpected result: %t, %v", ok, err)
+		}
+	})
+
+	t.Run("too many reassemblies", func(t *testing.T) {
+		r := sccp.NewReassembler()
+		r.MaxReassemblies = 1
+
+		if _, _, err := r.Reassemble(segment(t)[0]); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := r.Reassemble(segment(t)[0])
+		reassemblyError(t, err)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		r := sccp.NewReassembler()
+		r.MaxLen = len(data) - 1
+
+		var err error
+		for _, x := range segment(t) {
+			if _, _, err = r.Reassemble(x); err != nil {
+				break
+			}
+		}
+		reassemblyError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		expired := make(chan *sccp.ReassemblyError, 1)
+		r := sccp.NewReassembler()
+		r.Timeout = 50 * time.Millisecond
+		r.Expired = func(err *sccp.ReassemblyError) { expired <- err }
+
+		if _, _, err := r.Reassemble(segment(t)[0]); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-expired:
+			if _, ok := err.Service.(*sccp.XUDTS); !ok {
+				t.Errorf("expected XUDTS, got %v", err.Service)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("T(reass) did not expire")
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RLC represents a SCCP Message Release complete (RLC).
+type RLC struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+}
+
+// NewRLC creates a new RLC.
+func NewRLC(dlr, slr uint32) *RLC {
+	return &RLC{
+		Type:                      MsgTypeRLC,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a RLC instance.
+func (r *RLC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RLC) MarshalTo(b []byte) error {
+	if len(b) < r.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(r.Type)
+
+	n := 1
+	m, err := r.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := r.SourceLocalReference.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseRLC decodes given byte sequence as a SCCP RLC.
+func ParseRLC(b []byte) (*RLC, error) {
+	r := &RLC{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RLC.
+func (r *RLC) UnmarshalBinary(b []byte) error {
+	if len(b) < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	r.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.SourceLocalReference, _, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (r *RLC) MarshalLen() int {
+	return 7 // MsgType + DestinationLocalReference + SourceLocalReference
+}
+
+// String returns the RLC values in human readable format.
+func (r *RLC) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RLC) MessageType() MsgType {
+	return MsgTypeRLC
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RLC) MessageTypeName() string {
+	return r.MessageType().String()
+}
@@ -0,0 +1,241 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RLSD represents a SCCP Message Released (RLSD).
+type RLSD struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ReleaseCause              *params.ReleaseCause
+	Data                      *params.Data
+	Importance                *params.Importance
+	EndOfOptionalParameters   *params.EndOfOptionalParameters
+
+	ptr1 uint8
+}
+
+// NewRLSD creates a new RLSD.
+//
+// The optional parameters given as opts should be the ones created as optional,
+// e.g., params.NewDataOptional.
+func NewRLSD(dlr, slr uint32, cause params.ReleaseCauseValue, opts ...params.Parameter) *RLSD {
+	r := &RLSD{
+		Type:                      MsgTypeRLSD,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ReleaseCause:              params.NewCause(cause),
+	}
+
+	r.ptr1 = 0
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeData:
+			r.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			r.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			r.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			logf("unexpected parameter: %s in NewRLSD", opt.Code())
+		}
+	}
+
+	if len(opts) > 0 {
+		r.ptr1 = 1
+		// so that users don't have to give EndOfOptionalParameters explicitly
+		r.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
+	}
+
+	return r
+}
+
+// MarshalBinary returns the byte sequence generated from a RLSD instance.
+func (r *RLSD) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (r *RLSD) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 9 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(r.Type)
+
+	n := 1
+	m, err := r.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = r.SourceLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = r.ReleaseCause.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = r.ptr1
+	if r.ptr1 == 0 {
+		return nil
+	}
+
+	offset := n + int(r.ptr1)
+	if l < offset {
+		return io.ErrUnexpectedEOF
+	}
+	for _, param := range r.optionalParameters() {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+
+	return nil
+}
+
+// ParseRLSD decodes given byte sequence as a SCCP RLSD.
+func ParseRLSD(b []byte) (*RLSD, error) {
+	r := &RLSD{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RLSD.
+func (r *RLSD) UnmarshalBinary(b []byte) error {
+	l := len(b)
+	if l < 9 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	r.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.SourceLocalReference, n, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.ReleaseCause, n, err = params.ParseReleaseCause(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.ptr1 = b[offset]
+	if r.ptr1 == 0 {
+		return nil
+	}
+
+	offsetPtr1 := offset + int(r.ptr1)
+	if l < offsetPtr1+1 { // where optional parameters start
+		return io.ErrUnexpectedEOF
+	}
+
+	opts, _, err := params.ParseOptionalParameters(b[offsetPtr1:])
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeData:
+			r.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			r.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			r.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		}
+	}
+
+	return nil
+}
+
+// optionalParameters returns the optional parameters that are present, in the order to be serialized.
+func (r *RLSD) optionalParameters() []params.Parameter {
+	var opts []params.Parameter
+	if param := r.Data; param != nil {
+		opts = append(opts, param)
+	}
+	if param := r.Importance; param != nil {
+		opts = append(opts, param)
+	}
+	if param := r.EndOfOptionalParameters; param != nil {
+		opts = append(opts, param)
+	}
+
+	return opts
+}
+
+// MarshalLen returns the serial length.
+func (r *RLSD) MarshalLen() int {
+	l := 9 // MsgType + DestinationLocalReference + SourceLocalReference + ReleaseCause + Pointer
+
+	// if optional parameters exist
+	if r.ptr1 != 0 {
+		for _, param := range r.optionalParameters() {
+			l += param.MarshalLen()
+		}
+	}
+
+	return l
+}
+
+// String returns the RLSD values in human readable format.
+func (r *RLSD) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ReleaseCause: %s, Data: %v, Importance: %v}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+		r.ReleaseCause,
+		r.Data,
+		r.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RLSD) MessageType() MsgType {
+	return MsgTypeRLSD
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RLSD) MessageTypeName() string {
+	return r.MessageType().String()
+}
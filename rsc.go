@@ -0,0 +1,119 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RSC represents a SCCP Message Reset confirm (RSC).
+type RSC struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+}
+
+// NewRSC creates a new RSC.
+func NewRSC(dlr, slr uint32) *RSC {
+	return &RSC{
+		Type:                      MsgTypeRSC,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a RSC instance.
+func (r *RSC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RSC) MarshalTo(b []byte) error {
+	if len(b) < r.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(r.Type)
+
+	n := 1
+	m, err := r.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := r.SourceLocalReference.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseRSC decodes given byte sequence as a SCCP RSC.
+func ParseRSC(b []byte) (*RSC, error) {
+	r := &RSC{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RSC.
+func (r *RSC) UnmarshalBinary(b []byte) error {
+	if len(b) < 7 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	r.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.SourceLocalReference, _, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (r *RSC) MarshalLen() int {
+	return 7 // MsgType + DestinationLocalReference + SourceLocalReference
+}
+
+// String returns the RSC values in human readable format.
+func (r *RSC) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RSC) MessageType() MsgType {
+	return MsgTypeRSC
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RSC) MessageTypeName() string {
+	return r.MessageType().String()
+}
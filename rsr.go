@@ -0,0 +1,134 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RSR represents a SCCP Message Reset request (RSR).
+type RSR struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ResetCause                *params.ResetCause
+}
+
+// NewRSR creates a new RSR.
+func NewRSR(dlr, slr uint32, cause params.ResetCauseValue) *RSR {
+	return &RSR{
+		Type:                      MsgTypeRSR,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ResetCause:                params.NewCause(cause),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a RSR instance.
+func (r *RSR) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RSR) MarshalTo(b []byte) error {
+	if len(b) < r.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(r.Type)
+
+	n := 1
+	m, err := r.DestinationLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = r.SourceLocalReference.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	if _, err := r.ResetCause.Write(b[n:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseRSR decodes given byte sequence as a SCCP RSR.
+func ParseRSR(b []byte) (*RSR, error) {
+	r := &RSR{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RSR.
+func (r *RSR) UnmarshalBinary(b []byte) error {
+	if len(b) < 8 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+
+	offset := 1
+	var err error
+	var n int
+	r.DestinationLocalReference, n, err = params.ParseDestinationLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.SourceLocalReference, n, err = params.ParseSourceLocalReference(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	r.ResetCause, _, err = params.ParseResetCause(b[offset:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (r *RSR) MarshalLen() int {
+	return 8 // MsgType + DestinationLocalReference + SourceLocalReference + ResetCause
+}
+
+// String returns the RSR values in human readable format.
+func (r *RSR) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ResetCause: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+		r.ResetCause,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RSR) MessageType() MsgType {
+	return MsgTypeRSR
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RSR) MessageTypeName() string {
+	return r.MessageType().String()
+}
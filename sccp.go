@@ -14,6 +14,8 @@ import (
 	"encoding"
 	"fmt"
 	"io"
+
+	"github.com/wmnsk/go-sccp/params"
 )
 
 // MsgType is type of SCCP message.
@@ -55,46 +57,79 @@ type Message interface {
 	fmt.Stringer
 }
 
+// variantUnmarshaler is implemented by the Messages that contain the parameters
+// whose encoding differs by protocol variant, e.g., Called/Calling Party Address.
+type variantUnmarshaler interface {
+	unmarshalBinaryWithVariant(v params.Variant, b []byte) error
+}
+
 // ParseMessage decodes the byte sequence into Message by Message Type.
+//
+// The parameters are decoded in ITU-T format. Use ParseMessageWithVariant for the others.
 func ParseMessage(b []byte) (Message, error) {
+	return ParseMessageWithVariant(params.VariantITU, b)
+}
+
+// ParseMessageWithVariant decodes the byte sequence into Message by Message Type,
+// in the given protocol variant.
+func ParseMessageWithVariant(v params.Variant, b []byte) (Message, error) {
 	if len(b) < 1 {
 		return nil, fmt.Errorf("invalid SCCP message %v: %w", b, io.ErrUnexpectedEOF)
 	}
 
 	var m Message
 	switch MsgType(b[0]) {
-	/* TODO: implement!
 	case MsgTypeCR:
+		m = &CR{}
 	case MsgTypeCC:
+		m = &CC{}
 	case MsgTypeCREF:
+		m = &CREF{}
 	case MsgTypeRLSD:
+		m = &RLSD{}
 	case MsgTypeRLC:
+		m = &RLC{}
 	case MsgTypeDT1:
+		m = &DT1{}
 	case MsgTypeDT2:
+		m = &DT2{}
 	case MsgTypeAK:
-	*/
+		m = &AK{}
 	case MsgTypeUDT:
 		m = &UDT{}
-	/* TODO: implement!
 	case MsgTypeUDTS:
+		m = &UDTS{}
 	case MsgTypeED:
+		m = &ED{}
 	case MsgTypeEA:
+		m = &EA{}
 	case MsgTypeRSR:
+		m = &RSR{}
 	case MsgTypeRSC:
+		m = &RSC{}
 	case MsgTypeERR:
+		m = &ERR{}
 	case MsgTypeIT:
-	*/
+		m = &IT{}
 	case MsgTypeXUDT:
 		m = &XUDT{}
-	/* TODO: implement!
 	case MsgTypeXUDTS:
+		m = &XUDTS{}
 	case MsgTypeLUDT:
+		m = &LUDT{}
 	case MsgTypeLUDTS:
-	*/
+		m = &LUDTS{}
 	default:
 		return nil, UnsupportedTypeError(b[0])
 	}
 
+	if vu, ok := m.(variantUnmarshaler); ok {
+		if err := vu.unmarshalBinaryWithVariant(v, b); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
 	if err := m.UnmarshalBinary(b); err != nil {
 		return nil, err
 	}
@@ -6,6 +6,8 @@ package sccp_test
 
 import (
 	"encoding"
+	"encoding/binary"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -27,6 +29,397 @@ var testcases = []struct {
 	serialized  []byte
 	parseFunc   func([]byte) (serializable, error)
 }{
+	{
+		description: "CR/No optionals",
+		structured: sccp.NewCR(
+			0x123456, // Source Local Reference
+			2,        // Protocol Class
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x1234, 254, nil, // SPC, SSN, GT
+			),
+		),
+		serialized: []byte{
+			0x01,             // MsgType
+			0x12, 0x34, 0x56, // Source Local Reference
+			0x02,       // Protocol Class
+			0x02, 0x00, // Pointers
+			0x04, 0x43, 0x34, 0x12, 0xfe, // CdPA
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCR(b)
+		},
+	},
+	{
+		description: "CR/with optionals",
+		structured: sccp.NewCR(
+			0x123456, // Source Local Reference
+			3,        // Protocol Class
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x1234, 254, nil, // SPC, SSN, GT
+			),
+			params.NewCreditOptional(8),
+			params.NewCallingPartyAddressOptional(
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x4321, 254, nil, // SPC, SSN, GT
+			),
+			params.NewDataOptional([]byte{0xde, 0xad, 0xbe, 0xef}),
+			params.NewHopCounterOptional(15),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x01,             // MsgType
+			0x12, 0x34, 0x56, // Source Local Reference
+			0x03,       // Protocol Class
+			0x02, 0x06, // Pointers
+			0x04, 0x43, 0x34, 0x12, 0xfe, // CdPA
+			0x09, 0x01, 0x08, // Credit
+			0x04, 0x04, 0x43, 0x21, 0x43, 0xfe, // CgPA
+			0x0f, 0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x11, 0x01, 0x0f, // Hop Counter
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCR(b)
+		},
+	},
+	{
+		description: "CC/No optionals",
+		structured: sccp.NewCC(
+			0x123456, // Destination Local Reference
+			0x654321, // Source Local Reference
+			2,        // Protocol Class
+		),
+		serialized: []byte{
+			0x02,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x65, 0x43, 0x21, // Source Local Reference
+			0x02, // Protocol Class
+			0x00, // Pointer
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCC(b)
+		},
+	},
+	{
+		description: "CC/with optionals",
+		structured: sccp.NewCC(
+			0x123456, // Destination Local Reference
+			0x654321, // Source Local Reference
+			3,        // Protocol Class
+			params.NewCreditOptional(8),
+			params.NewCalledPartyAddressOptional(
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x1234, 254, nil, // SPC, SSN, GT
+			),
+			params.NewDataOptional([]byte{0xde, 0xad, 0xbe, 0xef}),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x02,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x65, 0x43, 0x21, // Source Local Reference
+			0x03,             // Protocol Class
+			0x01,             // Pointer
+			0x09, 0x01, 0x08, // Credit
+			0x03, 0x04, 0x43, 0x34, 0x12, 0xfe, // CdPA
+			0x0f, 0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCC(b)
+		},
+	},
+	{
+		description: "CREF/No optionals",
+		structured: sccp.NewCREF(
+			0x123456, // Destination Local Reference
+			params.RefusalCauseDestinationAddressUnknown,
+		),
+		serialized: []byte{
+			0x03,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x04, // Refusal Cause
+			0x00, // Pointer
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCREF(b)
+		},
+	},
+	{
+		description: "CREF/with optionals",
+		structured: sccp.NewCREF(
+			0x123456, // Destination Local Reference
+			params.RefusalCauseUnequippedUser,
+			params.NewCalledPartyAddressOptional(
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x1234, 254, nil, // SPC, SSN, GT
+			),
+			params.NewDataOptional([]byte{0xde, 0xad, 0xbe, 0xef}),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x03,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x13,                               // Refusal Cause
+			0x01,                               // Pointer
+			0x03, 0x04, 0x43, 0x34, 0x12, 0xfe, // CdPA
+			0x0f, 0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseCREF(b)
+		},
+	},
+	{
+		description: "RLSD/No optionals",
+		structured: sccp.NewRLSD(
+			0x123456, // Destination Local Reference
+			0x654321, // Source Local Reference
+			params.ReleaseCauseEndUserOriginated,
+		),
+		serialized: []byte{
+			0x04,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x65, 0x43, 0x21, // Source Local Reference
+			0x00, // Release Cause
+			0x00, // Pointer
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseRLSD(b)
+		},
+	},
+	{
+		description: "RLSD/with optionals",
+		structured: sccp.NewRLSD(
+			0x123456, // Destination Local Reference
+			0x654321, // Source Local Reference
+			params.ReleaseCauseSCCPUserOriginated,
+			params.NewDataOptional([]byte{0xde, 0xad, 0xbe, 0xef}),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x04,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x65, 0x43, 0x21, // Source Local Reference
+			0x03,                               // Release Cause
+			0x01,                               // Pointer
+			0x0f, 0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseRLSD(b)
+		},
+	},
+	{
+		description: "RLC",
+		structured: sccp.NewRLC(
+			0x654321, // Destination Local Reference
+			0x123456, // Source Local Reference
+		),
+		serialized: []byte{
+			0x05,             // MsgType
+			0x65, 0x43, 0x21, // Destination Local Reference
+			0x12, 0x34, 0x56, // Source Local Reference
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseRLC(b)
+		},
+	},
+	{
+		description: "DT1/More data",
+		structured: sccp.NewDT1(
+			0x123456, // Destination Local Reference
+			true,     // More data
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x06,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x01,                         // Segmenting/Reassembling
+			0x01,                         // Pointer
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseDT1(b)
+		},
+	},
+	{
+		description: "DT1/No more data",
+		structured: sccp.NewDT1(
+			0x123456, // Destination Local Reference
+			false,    // More data
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x06,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x00,                         // Segmenting/Reassembling
+			0x01,                         // Pointer
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseDT1(b)
+		},
+	},
+	{
+		description: "DT2/More data",
+		structured: sccp.NewDT2(
+			0x123456,   // Destination Local Reference
+			0x08, 0x10, // P(S), P(R)
+			true, // More data
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x07,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x10, 0x21, // Sequencing/Segmenting
+			0x01,                         // Pointer
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseDT2(b)
+		},
+	},
+	{
+		description: "DT2/No more data",
+		structured: sccp.NewDT2(
+			0x123456,   // Destination Local Reference
+			0x08, 0x10, // P(S), P(R)
+			false, // More data
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x07,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x10, 0x20, // Sequencing/Segmenting
+			0x01,                         // Pointer
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseDT2(b)
+		},
+	},
+	{
+		description: "AK",
+		structured: sccp.NewAK(
+			0x123456, // Destination Local Reference
+			0x10,     // P(R)
+			8,        // Credit
+		),
+		serialized: []byte{
+			0x08,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x20, // Receive Sequence Number
+			0x08, // Credit
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseAK(b)
+		},
+	},
+	{
+		description: "ED",
+		structured: sccp.NewED(
+			0x123456, // Destination Local Reference
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x0b,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x01,                         // Pointer
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseED(b)
+		},
+	},
+	{
+		description: "EA",
+		structured: sccp.NewEA(
+			0x123456, // Destination Local Reference
+		),
+		serialized: []byte{
+			0x0c,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseEA(b)
+		},
+	},
+	{
+		description: "RSR",
+		structured: sccp.NewRSR(
+			0x654321, // Destination Local Reference
+			0x123456, // Source Local Reference
+			params.ResetCauseMessageOutOfOrderIncorrectReceiveSequenceNumber,
+		),
+		serialized: []byte{
+			0x0d,             // MsgType
+			0x65, 0x43, 0x21, // Destination Local Reference
+			0x12, 0x34, 0x56, // Source Local Reference
+			0x03, // Reset Cause
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseRSR(b)
+		},
+	},
+	{
+		description: "RSC",
+		structured: sccp.NewRSC(
+			0x654321, // Destination Local Reference
+			0x123456, // Source Local Reference
+		),
+		serialized: []byte{
+			0x0e,             // MsgType
+			0x65, 0x43, 0x21, // Destination Local Reference
+			0x12, 0x34, 0x56, // Source Local Reference
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseRSC(b)
+		},
+	},
+	{
+		description: "ERR",
+		structured: sccp.NewERR(
+			0x123456, // Destination Local Reference
+			params.ErrorCauseServiceClassMismatch,
+		),
+		serialized: []byte{
+			0x0f,             // MsgType
+			0x12, 0x34, 0x56, // Destination Local Reference
+			0x03, // Error Cause
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseERR(b)
+		},
+	},
+	{
+		description: "IT",
+		structured: sccp.NewIT(
+			0x654321,   // Destination Local Reference
+			0x123456,   // Source Local Reference
+			3,          // Protocol Class
+			0x08, 0x10, // P(S), P(R)
+			false, // More data
+			8,     // Credit
+		),
+		serialized: []byte{
+			0x10,             // MsgType
+			0x65, 0x43, 0x21, // Destination Local Reference
+			0x12, 0x34, 0x56, // Source Local Reference
+			0x03,       // Protocol Class
+			0x10, 0x20, // Sequencing/Segmenting
+			0x08, // Credit
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseIT(b)
+		},
+	},
 	{
 		description: "UDT",
 		structured: sccp.NewUDT(
@@ -34,61 +427,433 @@ var testcases = []struct {
 			true, // Message handling
 			params.NewCalledPartyAddress(
 				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
-				0, 6, // SPC, SSN
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x09,
+			0x81,
+			0x03, 0x10, 0x1a,
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65,
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01,
+			0x04, 0xde, 0xad, 0xbe, 0xef,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDT(b)
+		},
+	},
+	{
+		description: "UDT-2Bytes-PartyAddress",
+		structured: sccp.NewUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			params.NewCalledPartyAddress(0x42, 0, 6, nil),
+			params.NewCallingPartyAddress(0x42, 0, 7, nil),
+			nil,
+		),
+		serialized: []byte{
+			0x09, 0x81, 0x03, 0x05, 0x07, 0x02, 0x42, 0x06, 0x02, 0x42, 0x07, 0x00,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDT(b)
+		},
+	},
+	{
+		description: "UDT/ANSI",
+		structured: sccp.NewUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			params.NewPartyAddressWithVariant(
+				params.VariantANSI,
+				params.PCodeCalledPartyAddress,
+				params.NewANSIAddressIndicator(true, true, true, params.GTINoGT),
+				0x0a0b0c, 8, nil, // SPC, SSN, GT
+			),
+			params.NewPartyAddressWithVariant(
+				params.VariantANSI,
+				params.PCodeCallingPartyAddress,
+				params.NewANSIAddressIndicator(false, true, false, params.GTIANSITTNPES),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitleWithVariant(
+					params.VariantANSI,
+					params.GTIANSITTNPES,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIUnknown,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x09,             // MsgType
+			0x81,             // Protocol Class
+			0x03, 0x08, 0x11, // Pointers
+			0x05, 0xc3, 0x08, 0x0c, 0x0b, 0x0a, // CdPA
+			0x09, 0x85, 0x07, 0x00, 0x12, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDTWithVariant(params.VariantANSI, b)
+		},
+	},
+	{
+		description: "UDT/China",
+		structured: sccp.NewUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			params.NewPartyAddressWithVariant(
+				params.VariantChina,
+				params.PCodeCalledPartyAddress,
+				params.NewAddressIndicator(true, true, true, params.GTINoGT),
+				0x0a0b0c, 8, nil, // SPC, SSN, GT
+			),
+			params.NewPartyAddressWithVariant(
+				params.VariantChina,
+				params.PCodeCallingPartyAddress,
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitleWithVariant(
+					params.VariantChina,
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x09,             // MsgType
+			0x81,             // Protocol Class
+			0x03, 0x08, 0x12, // Pointers
+			0x05, 0x43, 0x0c, 0x0b, 0x0a, 0x08, // CdPA
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDTWithVariant(params.VariantChina, b)
+		},
+	},
+	{
+		description: "UDTS",
+		structured: sccp.NewUDTS(
+			params.ReturnCauseNoTranslationForThisSpecificAddress,
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x0a,             // MsgType
+			0x01,             // Return Cause
+			0x03, 0x0d, 0x1a, // Pointers
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDTS(b)
+		},
+	},
+	{
+		description: "UDTS-2Bytes-PartyAddress",
+		structured: sccp.NewUDTS(
+			params.ReturnCauseSubsystemFailure,
+			params.NewCalledPartyAddress(0x42, 0, 7, nil),
+			params.NewCallingPartyAddress(0x42, 0, 6, nil),
+			nil,
+		),
+		serialized: []byte{
+			0x0a, 0x03, 0x03, 0x05, 0x07, 0x02, 0x42, 0x07, 0x02, 0x42, 0x06, 0x00,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseUDTS(b)
+		},
+	},
+	{
+		description: "XUDT/No optionals",
+		structured: sccp.NewXUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			2,    // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x11,                   // MsgType
+			0x81,                   // Protocol Class
+			0x02,                   // Hop Counter
+			0x04, 0x11, 0x1b, 0x00, // Pointers
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseXUDT(b)
+		},
+	},
+	{
+		description: "XUDT/with optionals",
+		structured: sccp.NewXUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			2,    // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+			params.NewSegmentation(true, 1, 2, 0xffffff),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x11,                   // MsgType
+			0x81,                   // Protocol Class
+			0x02,                   // Hop Counter
+			0x04, 0x11, 0x1b, 0x1f, // Pointers
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseXUDT(b)
+		},
+	},
+	{
+		description: "XUDT/ANSI with optionals",
+		structured: sccp.NewXUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			2,    // Hop Counter
+			params.NewPartyAddressWithVariant(
+				params.VariantANSI,
+				params.PCodeCalledPartyAddress,
+				params.NewANSIAddressIndicator(true, true, true, params.GTINoGT),
+				0x0a0b0c, 8, nil, // SPC, SSN, GT
+			),
+			params.NewPartyAddressWithVariant(
+				params.VariantANSI,
+				params.PCodeCallingPartyAddress,
+				params.NewANSIAddressIndicator(false, true, false, params.GTIANSITTNPES),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitleWithVariant(
+					params.VariantANSI,
+					params.GTIANSITTNPES,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIUnknown,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+			params.NewSegmentation(true, 1, 2, 0xffffff),
+			params.NewImportance(2),
+		),
+		serialized: []byte{
+			0x11,                   // MsgType
+			0x81,                   // Protocol Class
+			0x02,                   // Hop Counter
+			0x04, 0x09, 0x12, 0x16, // Pointers
+			0x05, 0xc3, 0x08, 0x0c, 0x0b, 0x0a, // CdPA
+			0x09, 0x85, 0x07, 0x00, 0x12, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseXUDTWithVariant(params.VariantANSI, b)
+		},
+	},
+	{
+		description: "XUDTS/No optionals",
+		structured: sccp.NewXUDTS(
+			params.ReturnCauseNoTranslationForThisSpecificAddress,
+			15, // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
 				params.NewGlobalTitle(
 					params.GTITTNPESNAI,
 					params.TranslationType(0),
 					params.NPISDNTelephony,
-					params.ESBCDOdd,
+					params.ESBCDEven,
 					params.NAIInternationalNumber,
-					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
 				),
 			),
 			params.NewCallingPartyAddress(
 				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
-				0, 7, // SPC, SSN
+				0, 6, // SPC, SSN
 				params.NewGlobalTitle(
 					params.GTITTNPESNAI,
 					params.TranslationType(0),
 					params.NPISDNTelephony,
-					params.ESBCDEven,
+					params.ESBCDOdd,
 					params.NAIInternationalNumber,
-					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
 				),
 			),
 			[]byte{0xde, 0xad, 0xbe, 0xef},
 		),
 		serialized: []byte{
-			0x09,
-			0x81,
-			0x03, 0x10, 0x1a,
-			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65,
-			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01,
-			0x04, 0xde, 0xad, 0xbe, 0xef,
+			0x12,                   // MsgType
+			0x01,                   // Return Cause
+			0x0f,                   // Hop Counter
+			0x04, 0x0e, 0x1b, 0x00, // Pointers
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
 		},
 		parseFunc: func(b []byte) (serializable, error) {
-			return sccp.ParseUDT(b)
+			return sccp.ParseXUDTS(b)
 		},
 	},
 	{
-		description: "UDT-2Bytes-PartyAddress",
-		structured: sccp.NewUDT(
-			1,    // Protocol Class
-			true, // Message handling
-			params.NewCalledPartyAddress(0x42, 0, 6, nil),
-			params.NewCallingPartyAddress(0x42, 0, 7, nil),
-			nil,
+		description: "XUDTS/with optionals",
+		structured: sccp.NewXUDTS(
+			params.ReturnCauseSegmentationFailure,
+			15, // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+			params.NewSegmentation(true, 1, 2, 0xffffff),
+			params.NewImportance(2),
 		),
 		serialized: []byte{
-			0x09, 0x81, 0x03, 0x05, 0x07, 0x02, 0x42, 0x06, 0x02, 0x42, 0x07, 0x00,
+			0x12,                   // MsgType
+			0x0e,                   // Return Cause
+			0x0f,                   // Hop Counter
+			0x04, 0x0e, 0x1b, 0x1f, // Pointers
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
+			0x12, 0x01, 0x02, // Importance
+			0x00, // End of optional parameters
 		},
 		parseFunc: func(b []byte) (serializable, error) {
-			return sccp.ParseUDT(b)
+			return sccp.ParseXUDTS(b)
 		},
 	},
 	{
-		description: "XUDT/No optionals",
-		structured: sccp.NewXUDT(
+		description: "LUDT/No optionals",
+		structured: sccp.NewLUDT(
 			1,    // Protocol Class
 			true, // Message handling
 			2,    // Hop Counter
@@ -119,21 +884,21 @@ var testcases = []struct {
 			[]byte{0xde, 0xad, 0xbe, 0xef},
 		),
 		serialized: []byte{
-			0x11,                   // MsgType
-			0x81,                   // Protocol Class
-			0x02,                   // Hop Counter
-			0x04, 0x11, 0x1b, 0x00, // Pointers
+			0x13,                                           // MsgType
+			0x81,                                           // Protocol Class
+			0x02,                                           // Hop Counter
+			0x08, 0x00, 0x14, 0x00, 0x1d, 0x00, 0x00, 0x00, // Pointers
 			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
 			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
-			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x04, 0x00, 0xde, 0xad, 0xbe, 0xef, // Long Data
 		},
 		parseFunc: func(b []byte) (serializable, error) {
-			return sccp.ParseXUDT(b)
+			return sccp.ParseLUDT(b)
 		},
 	},
 	{
-		description: "XUDT/with optionals",
-		structured: sccp.NewXUDT(
+		description: "LUDT/with optionals",
+		structured: sccp.NewLUDT(
 			1,    // Protocol Class
 			true, // Message handling
 			2,    // Hop Counter
@@ -166,19 +931,170 @@ var testcases = []struct {
 			params.NewImportance(2),
 		),
 		serialized: []byte{
-			0x11,                   // MsgType
-			0x81,                   // Protocol Class
-			0x02,                   // Hop Counter
-			0x04, 0x11, 0x1b, 0x1f, // Pointers
+			0x13,                                           // MsgType
+			0x81,                                           // Protocol Class
+			0x02,                                           // Hop Counter
+			0x08, 0x00, 0x14, 0x00, 0x1d, 0x00, 0x21, 0x00, // Pointers
 			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
 			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
-			0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			0x04, 0x00, 0xde, 0xad, 0xbe, 0xef, // Long Data
 			0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
 			0x12, 0x01, 0x02, // Importance
 			0x00, // End of optional parameters
 		},
 		parseFunc: func(b []byte) (serializable, error) {
-			return sccp.ParseXUDT(b)
+			return sccp.ParseLUDT(b)
+		},
+	},
+	{
+		description: "LUDT/300 bytes with optionals",
+		structured: sccp.NewLUDT(
+			1,    // Protocol Class
+			true, // Message handling
+			2,    // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			sequentialBytes(300),
+			params.NewImportance(2),
+		),
+		serialized: concat(
+			[]byte{
+				0x13,                                           // MsgType
+				0x81,                                           // Protocol Class
+				0x02,                                           // Hop Counter
+				0x08, 0x00, 0x14, 0x00, 0x1d, 0x00, 0x49, 0x01, // Pointers
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+				0x2c, 0x01, // Long Data length
+			},
+			sequentialBytes(300), // Long Data
+			[]byte{
+				0x12, 0x01, 0x02, // Importance
+				0x00, // End of optional parameters
+			},
+		),
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseLUDT(b)
+		},
+	},
+	{
+		description: "LUDTS/No optionals",
+		structured: sccp.NewLUDTS(
+			params.ReturnCauseMTPFailure,
+			15, // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			[]byte{0xde, 0xad, 0xbe, 0xef},
+		),
+		serialized: []byte{
+			0x14,                                           // MsgType
+			0x05,                                           // Return Cause
+			0x0f,                                           // Hop Counter
+			0x08, 0x00, 0x11, 0x00, 0x1d, 0x00, 0x00, 0x00, // Pointers
+			0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+			0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+			0x04, 0x00, 0xde, 0xad, 0xbe, 0xef, // Long Data
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseLUDTS(b)
+		},
+	},
+	{
+		description: "LUDTS/with optionals",
+		structured: sccp.NewLUDTS(
+			params.ReturnCauseSegmentationFailure,
+			15, // Hop Counter
+			params.NewCalledPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 7, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDEven,
+					params.NAIInternationalNumber,
+					[]byte{0x89, 0x67, 0x45, 0x23, 0x01},
+				),
+			),
+			params.NewCallingPartyAddress(
+				params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+				0, 6, // SPC, SSN
+				params.NewGlobalTitle(
+					params.GTITTNPESNAI,
+					params.TranslationType(0),
+					params.NPISDNTelephony,
+					params.ESBCDOdd,
+					params.NAIInternationalNumber,
+					[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+				),
+			),
+			sequentialBytes(300),
+			params.NewSegmentation(true, 1, 2, 0xffffff),
+			params.NewImportance(2),
+		),
+		serialized: concat(
+			[]byte{
+				0x14,                                           // MsgType
+				0x0e,                                           // Return Cause
+				0x0f,                                           // Hop Counter
+				0x08, 0x00, 0x11, 0x00, 0x1d, 0x00, 0x49, 0x01, // Pointers
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+				0x2c, 0x01, // Long Data length
+			},
+			sequentialBytes(300), // Long Data
+			[]byte{
+				0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
+				0x12, 0x01, 0x02, // Importance
+				0x00, // End of optional parameters
+			},
+		),
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseLUDTS(b)
 		},
 	},
 	{
@@ -197,6 +1113,30 @@ var testcases = []struct {
 			return sccp.ParseSCMG(b)
 		},
 	},
+	{
+		description: "SCMG SSA/ANSI",
+		structured:  sccp.NewSCMGWithVariant(params.VariantANSI, sccp.SCMGTypeSSA, 9, 0x0a0b0c, 0, 0),
+		serialized:  []byte{0x01, 0x09, 0x0c, 0x0b, 0x0a, 0x00},
+		parseFunc: func(b []byte) (serializable, error) {
+			return sccp.ParseSCMGWithVariant(params.VariantANSI, b)
+		},
+	},
+}
+
+func sequentialBytes(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = uint8(i)
+	}
+	return b
+}
+
+func concat(bs ...[]byte) []byte {
+	var b []byte
+	for _, v := range bs {
+		b = append(b, v...)
+	}
+	return b
 }
 
 func TestMessages(t *testing.T) {
@@ -277,3 +1217,219 @@ func TestPartialStructuredMessages(t *testing.T) {
 		}
 	}
 }
+
+func TestInconsistentPointers(t *testing.T) {
+	cases := []struct {
+		description string
+		serialized  []byte
+		parseFunc   func(b []byte) (serializable, error)
+	}{
+		{
+			description: "UDTS/gap before CgPA",
+			serialized: []byte{
+				0x0a,             // MsgType
+				0x01,             // Return Cause
+				0x03, 0x0e, 0x1b, // Pointers
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0xff,                                                                               // Gap
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+				0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseUDTS(b)
+			},
+		},
+		{
+			description: "UDTS/CgPA before CdPA",
+			serialized: []byte{
+				0x0a,             // MsgType
+				0x01,             // Return Cause
+				0x11, 0x02, 0x1a, // Pointers
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseUDTS(b)
+			},
+		},
+		{
+			description: "XUDTS/gap before optional part",
+			serialized: []byte{
+				0x12,                   // MsgType
+				0x0e,                   // Return Cause
+				0x0f,                   // Hop Counter
+				0x04, 0x0e, 0x1b, 0x20, // Pointers
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+				0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+				0xff,                               // Gap
+				0x10, 0x04, 0xc2, 0xff, 0xff, 0xff, // Segmentation
+				0x12, 0x01, 0x02, // Importance
+				0x00, // End of optional parameters
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseXUDTS(b)
+			},
+		},
+		{
+			description: "XUDTS/Data before CgPA",
+			serialized: []byte{
+				0x12,                   // MsgType
+				0x0e,                   // Return Cause
+				0x0f,                   // Hop Counter
+				0x04, 0x13, 0x0d, 0x00, // Pointers
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0x04, 0xde, 0xad, 0xbe, 0xef, // Data
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseXUDTS(b)
+			},
+		},
+		{
+			description: "LUDT/gap before CgPA",
+			serialized: []byte{
+				0x13,                                           // MsgType
+				0x81,                                           // Protocol Class
+				0x02,                                           // Hop Counter
+				0x08, 0x00, 0x15, 0x00, 0x1e, 0x00, 0x00, 0x00, // Pointers
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CdPA
+				0xff,                                                             // Gap
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CgPA
+				0x04, 0x00, 0xde, 0xad, 0xbe, 0xef, // Long Data
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseLUDT(b)
+			},
+		},
+		{
+			description: "LUDTS/CgPA before CdPA",
+			serialized: []byte{
+				0x14,                                           // MsgType
+				0x05,                                           // Return Cause
+				0x0f,                                           // Hop Counter
+				0x16, 0x00, 0x06, 0x00, 0x1d, 0x00, 0x00, 0x00, // Pointers
+				0x0d, 0x12, 0x06, 0x00, 0x11, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65, // CgPA
+				0x0a, 0x12, 0x07, 0x00, 0x12, 0x04, 0x89, 0x67, 0x45, 0x23, 0x01, // CdPA
+				0x04, 0x00, 0xde, 0xad, 0xbe, 0xef, // Long Data
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseLUDTS(b)
+			},
+		},
+		{
+			description: "XUDTS/truncated pointers",
+			serialized: []byte{
+				0x12,             // MsgType
+				0x07,             // Return Cause
+				0x05,             // Hop Counter
+				0x00, 0x01, 0x00, // Pointers, without the one to the optional part
+			},
+			parseFunc: func(b []byte) (serializable, error) {
+				return sccp.ParseMessage(b)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if _, err := c.parseFunc(c.serialized); err != io.ErrUnexpectedEOF {
+				t.Errorf("got error %v, want unexpected EOF", err)
+			}
+		})
+	}
+}
+
+func TestParser(t *testing.T) {
+	parsers := []struct {
+		variant      params.Variant
+		descriptions []string
+	}{
+		{params.VariantITU, []string{"UDT", "XUDT/with optionals", "CR/with optionals", "SCMG SSC"}},
+		{params.VariantANSI, []string{"UDT/ANSI", "XUDT/ANSI with optionals", "SCMG SSA/ANSI"}},
+		{params.VariantChina, []string{"UDT/China"}},
+	}
+
+	for _, p := range parsers {
+		parser := sccp.NewParser(p.variant)
+		for _, desc := range p.descriptions {
+			t.Run(p.variant.String()+"/"+desc, func(t *testing.T) {
+				idx := -1
+				for i, c := range testcases {
+					if c.description == desc {
+						idx = i
+					}
+				}
+				if idx < 0 {
+					t.Fatalf("no test case named %s", desc)
+				}
+				c := testcases[idx]
+
+				var got serializable
+				var err error
+				if _, ok := c.structured.(*sccp.SCMG); ok {
+					got, err = parser.ParseSCMG(c.serialized)
+				} else {
+					got, err = parser.ParseMessage(c.serialized)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if want := c.structured; !verify.Values(t, "", got, want) {
+					t.Fail()
+				}
+			})
+		}
+	}
+}
+
+func TestTooLongData(t *testing.T) {
+	cdpa := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(true, true, true, params.GTINoGT), 1, 6, nil,
+	)
+	cgpa := params.NewCallingPartyAddress(
+		params.NewAddressIndicator(true, true, true, params.GTINoGT), 2, 7, nil,
+	)
+
+	t.Run("LUDT/marshal", func(t *testing.T) {
+		ludt := sccp.NewLUDT(1, false, 15, cdpa, cgpa, make([]byte, sccp.MaxLongDataLen+1))
+		if _, err := ludt.MarshalBinary(); !errors.Is(err, sccp.ErrTooLongData) {
+			t.Errorf("got error %v, want ErrTooLongData", err)
+		}
+	})
+
+	t.Run("LUDT/parse", func(t *testing.T) {
+		b, err := sccp.NewLUDT(1, false, 15, cdpa, cgpa, make([]byte, sccp.MaxLongDataLen)).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the length of the LongData placed at the end is incremented.
+		binary.LittleEndian.PutUint16(b[len(b)-sccp.MaxLongDataLen-2:], sccp.MaxLongDataLen+1)
+		b = append(b, 0)
+
+		if _, err := sccp.ParseLUDT(b); !errors.Is(err, sccp.ErrTooLongData) {
+			t.Errorf("got error %v, want ErrTooLongData", err)
+		}
+	})
+	t.Run("LUDTS/marshal", func(t *testing.T) {
+		ludts := sccp.NewLUDTS(params.ReturnCauseMTPFailure, 15, cdpa, cgpa, make([]byte, sccp.MaxLongDataLen+1))
+		if _, err := ludts.MarshalBinary(); !errors.Is(err, sccp.ErrTooLongData) {
+			t.Errorf("got error %v, want ErrTooLongData", err)
+		}
+	})
+
+	t.Run("LUDTS/parse", func(t *testing.T) {
+		b, err := sccp.NewLUDTS(params.ReturnCauseMTPFailure, 15, cdpa, cgpa, make([]byte, sccp.MaxLongDataLen)).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		binary.LittleEndian.PutUint16(b[len(b)-sccp.MaxLongDataLen-2:], sccp.MaxLongDataLen+1)
+		b = append(b, 0)
+
+		if _, err := sccp.ParseLUDTS(b); !errors.Is(err, sccp.ErrTooLongData) {
+			t.Errorf("got error %v, want ErrTooLongData", err)
+		}
+	})
+}
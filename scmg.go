@@ -5,9 +5,10 @@
 package sccp
 
 import (
-	"encoding/binary"
 	"fmt"
 	"io"
+
+	"github.com/wmnsk/go-sccp/params"
 )
 
 // SCMGType is type of SCMG message.
@@ -27,19 +28,28 @@ const (
 // SCMG represents a SCCP Management message (SCMG).
 // Chapter 5.3/Q.713
 type SCMG struct {
+	// Variant is the protocol variant that the SCMG is encoded in,
+	// which determines the length of AffectedPC.
+	Variant                        params.Variant
 	Type                           SCMGType
 	AffectedSSN                    uint8
-	AffectedPC                     uint16
+	AffectedPC                     params.PointCode
 	SubsystemMultiplicityIndicator uint8
 	SCCPCongestionLevel            uint8
 }
 
 // NewSCMG creates a new SCMG.
-func NewSCMG(typ SCMGType, assn uint8, apc uint16, smi uint8, scl uint8) *SCMG {
+func NewSCMG(typ SCMGType, assn uint8, apc uint32, smi uint8, scl uint8) *SCMG {
+	return NewSCMGWithVariant(params.VariantITU, typ, assn, apc, smi, scl)
+}
+
+// NewSCMGWithVariant creates a new SCMG in the given protocol variant.
+func NewSCMGWithVariant(v params.Variant, typ SCMGType, assn uint8, apc uint32, smi uint8, scl uint8) *SCMG {
 	return &SCMG{
+		Variant:                        v,
 		Type:                           typ,
 		AffectedSSN:                    assn,
-		AffectedPC:                     apc,
+		AffectedPC:                     v.NewPointCode(apc),
 		SubsystemMultiplicityIndicator: smi,
 		SCCPCongestionLevel:            scl,
 	}
@@ -65,10 +75,17 @@ func (s *SCMG) MarshalTo(b []byte) error {
 
 	b[0] = uint8(s.Type)
 	b[1] = s.AffectedSSN
-	binary.LittleEndian.PutUint16(b[2:4], s.AffectedPC)
-	b[4] = s.SubsystemMultiplicityIndicator
+
+	// the Affected PC is encoded in little endian, like the SPC in PartyAddress.
+	n := 2
+	for i := 0; i < s.Variant.PointCodeLen(); i++ {
+		b[n] = uint8(s.AffectedPC.Value >> (8 * i))
+		n++
+	}
+
+	b[n] = s.SubsystemMultiplicityIndicator
 	if s.Type == SCMGTypeSSC {
-		b[5] = s.SCCPCongestionLevel
+		b[n+1] = s.SCCPCongestionLevel
 	}
 
 	return nil
@@ -76,7 +93,12 @@ func (s *SCMG) MarshalTo(b []byte) error {
 
 // ParseSCMG decodes given byte sequence as a SCMG.
 func ParseSCMG(b []byte) (*SCMG, error) {
-	s := &SCMG{}
+	return ParseSCMGWithVariant(params.VariantITU, b)
+}
+
+// ParseSCMGWithVariant decodes given byte sequence as a SCMG in the given protocol variant.
+func ParseSCMGWithVariant(v params.Variant, b []byte) (*SCMG, error) {
+	s := &SCMG{Variant: v}
 	if err := s.UnmarshalBinary(b); err != nil {
 		return nil, err
 	}
@@ -85,22 +107,32 @@ func ParseSCMG(b []byte) (*SCMG, error) {
 }
 
 // UnmarshalBinary sets the values retrieved from byte sequence in a SCMG.
+//
+// The byte sequence is decoded in the Variant set in the SCMG beforehand.
 func (s *SCMG) UnmarshalBinary(b []byte) error {
 	l := len(b)
-	if l < 5 {
+	pcLen := s.Variant.PointCodeLen()
+	if l < 3+pcLen {
 		return io.ErrUnexpectedEOF
 	}
 
 	s.Type = SCMGType(b[0])
 	s.AffectedSSN = b[1]
-	s.AffectedPC = binary.LittleEndian.Uint16(b[2:4])
-	s.SubsystemMultiplicityIndicator = b[4]
+
+	n := 2
+	s.AffectedPC = s.Variant.NewPointCode(0)
+	for i := 0; i < pcLen; i++ {
+		s.AffectedPC.Value |= uint32(b[n]) << (8 * i)
+		n++
+	}
+
+	s.SubsystemMultiplicityIndicator = b[n]
 
 	if s.Type == SCMGTypeSSC {
-		if l < 6 {
+		if l < n+2 {
 			return io.ErrUnexpectedEOF
 		}
-		s.SCCPCongestionLevel = b[5]
+		s.SCCPCongestionLevel = b[n+1]
 	}
 
 	return nil
@@ -109,7 +141,7 @@ func (s *SCMG) UnmarshalBinary(b []byte) error {
 // MarshalLen returns the serial length.
 func (s *SCMG) MarshalLen() int {
 	// Table 24/Q.713 – SCMG messages
-	l := 5
+	l := 3 + s.Variant.PointCodeLen()
 
 	// Table 25/Q.713 – SSC
 	if s.Type == SCMGTypeSSC {
@@ -121,7 +153,7 @@ func (s *SCMG) MarshalLen() int {
 
 // String returns the SCMG values in human readable format.
 func (s *SCMG) String() string {
-	return fmt.Sprintf("%s: {AffectedSSN: %v, AffectedPC: %v, SubsystemMultiplicityIndicator: %d, SCCPCongestionLevel: %d}",
+	return fmt.Sprintf("%s: {AffectedSSN: %v, AffectedPC: %s, SubsystemMultiplicityIndicator: %d, SCCPCongestionLevel: %d}",
 		s.Type,
 		s.AffectedSSN,
 		s.AffectedPC,
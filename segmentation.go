@@ -0,0 +1,106 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MaxUserPartLenMTP3 is the maximum length of the MTP user part over MTP3, i.e., the
+// SIF of 272 octets including the routing label.
+const MaxUserPartLenMTP3 = 272
+
+// maxSegments is the maximum number of the segments of a user data.
+const maxSegments = 16
+
+// Segmenter splits the user data too long for a single XUDT into the segments
+// in the XUDTs with the Segmentation parameter. See Q.714 4.1.1.2.
+type Segmenter struct {
+	Variant params.Variant
+	// MaxUserPartLen is the maximum length of the MTP user part, which includes the
+	// routing label of the Variant. It is MaxUserPartLenMTP3 over MTP3, and can be
+	// larger over M3UA, though the data in an XUDT is limited to 255 octets anyway.
+	MaxUserPartLen int
+}
+
+// NewSegmenter creates a new Segmenter with the maximum length of the MTP user part.
+func NewSegmenter(v params.Variant, maxLen int) *Segmenter {
+	return &Segmenter{Variant: v, MaxUserPartLen: maxLen}
+}
+
+// Segment creates the XUDTs that carry the data.
+//
+// If the data fits in a single XUDT, it returns the XUDT without the Segmentation.
+// Otherwise, the data is split into up to 16 segments with a random local reference, and
+// the XUDTs are in protocol class 1 so that they are delivered in sequence, with the
+// protocol class given here kept in the Segmentation. All the XUDTs must be sent with
+// the same SLS.
+//
+// The protocol class must be 0 or 1. The opts are added to all the XUDTs, which can
+// be Importance.
+func (s *Segmenter) Segment(pcls int, retOnErr bool, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) ([]*XUDT, error) {
+	if pcls != 0 && pcls != 1 {
+		return nil, fmt.Errorf("sccp: unsupported protocol class: %d", pcls)
+	}
+
+	single, err := s.maxDataLen(cdpa, cgpa, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) <= single {
+		return []*XUDT{NewXUDT(pcls, retOnErr, hc, cdpa, cgpa, data, opts...)}, nil
+	}
+
+	segOpts := append([]params.Parameter{params.NewSegmentation(false, 0, 0, 0)}, opts...)
+	segLen, err := s.maxDataLen(cdpa, cgpa, segOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	n := (len(data) + segLen - 1) / segLen
+	if n > maxSegments {
+		return nil, fmt.Errorf("sccp: too long data: %d octets, must be <= %d", len(data), segLen*maxSegments)
+	}
+
+	lrn := rand.Uint32() & 0xffffff
+	xudts := make([]*XUDT, 0, n)
+	for i := 0; i < n; i++ {
+		seg := data[i*segLen : min((i+1)*segLen, len(data))]
+		segOpts[0] = params.NewSegmentation(i == 0, uint8(pcls), uint8(n-i-1), lrn)
+		xudts = append(xudts, NewXUDT(1, retOnErr, hc, cdpa, cgpa, seg, segOpts...))
+	}
+
+	return xudts, nil
+}
+
+// maxDataLen returns the maximum length of the data in an XUDT with the given parameters.
+func (s *Segmenter) maxDataLen(cdpa, cgpa *params.PartyAddress, opts ...params.Parameter) (int, error) {
+	x := NewXUDT(0, false, 0, cdpa, cgpa, nil, opts...)
+
+	l := s.MaxUserPartLen - s.routingLabelLen() - x.MarshalLen()
+	// the pointer to the optional part must not exceed 255.
+	if len(opts) > 0 {
+		l = min(l, 0xff-int(x.ptr4))
+	}
+	l = min(l, 0xff)
+
+	if l <= 0 {
+		return 0, fmt.Errorf("sccp: no room for data in %d octets of MTP user part", s.MaxUserPartLen)
+	}
+	return l, nil
+}
+
+// routingLabelLen returns the length of the routing label of the Variant.
+func (s *Segmenter) routingLabelLen() int {
+	w := int(s.Variant.NewPointCode(0).Width)
+	sls := 4
+	if w == int(params.PointCodeWidth24) {
+		sls = 8
+	}
+	return (2*w + sls + 7) / 8
+}
@@ -0,0 +1,114 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestSegmenter(t *testing.T) {
+	cdpa, cgpa := ssnAddr(2, 8), ssnAddr(1, 6)
+	s := sccp.NewSegmenter(params.VariantITU, sccp.MaxUserPartLenMTP3)
+
+	t.Run("single", func(t *testing.T) {
+		xudts, err := s.Segment(0, true, 15, cdpa, cgpa, make([]byte, 200))
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "segments", len(xudts), 1)
+		if xudts[0].Segmentation != nil {
+			t.Errorf("unexpected Segmentation: %s", xudts[0].Segmentation)
+		}
+	})
+
+	ansiAddr := func(cdcg params.ParameterNameCode, pc uint32, ssn uint8) *params.PartyAddress {
+		return params.NewPartyAddressWithVariant(
+			params.VariantANSI, cdcg,
+			params.NewANSIAddressIndicator(true, true, true, params.GTINoGT), pc, ssn, nil,
+		)
+	}
+
+	segCases := []struct {
+		description string
+		variant     params.Variant
+		maxLen      int
+		label       int // length of the routing label
+		cdpa, cgpa  *params.PartyAddress
+		// segLen is the length of the data in the segments but the last one.
+		segLen int
+	}{
+		{
+			"ITU/MTP3", params.VariantITU, sccp.MaxUserPartLenMTP3, 4,
+			cdpa, cgpa, 240,
+		}, {
+			"ANSI/MTP3", params.VariantANSI, sccp.MaxUserPartLenMTP3, 7,
+			ansiAddr(params.PCodeCalledPartyAddress, 0x020202, 8),
+			ansiAddr(params.PCodeCallingPartyAddress, 0x010101, 6),
+			235,
+		}, {
+			// limited by the pointer to the optional part.
+			"ITU/M3UA", params.VariantITU, 4096, 4,
+			cdpa, cgpa, 243,
+		},
+	}
+
+	for _, c := range segCases {
+		t.Run("segmented/"+c.description, func(t *testing.T) {
+			s := sccp.NewSegmenter(c.variant, c.maxLen)
+			data := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 750)
+			xudts, err := s.Segment(0, true, 15, c.cdpa, c.cgpa, data, params.NewImportanceOptional(3))
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "segments", len(xudts), (len(data)+c.segLen-1)/c.segLen)
+
+			var got []byte
+			lrn := xudts[0].Segmentation.LocalReference
+			for i, x := range xudts {
+				b, err := x.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(b) > c.maxLen-c.label {
+					t.Errorf("segment %d: too long: %d octets", i, len(b))
+				}
+
+				// the values after encoding and decoding.
+				p, err := sccp.ParseXUDTWithVariant(c.variant, b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				seg := p.Segmentation
+				verify.Values(t, "FirstSegment", seg.FirstSegment, i == 0)
+				verify.Values(t, "RemainingSegments", seg.RemainingSegments, uint8(len(xudts)-i-1))
+				verify.Values(t, "LocalReference", seg.LocalReference, lrn)
+				verify.Values(t, "ProtocolClass", p.ProtocolClass.Class(), 1)
+				verify.Values(t, "Importance", p.Importance.Value(), uint8(3))
+				if i < len(xudts)-1 {
+					verify.Values(t, "segment length", len(p.Data.Value()), c.segLen)
+				}
+				got = append(got, p.Data.Value()...)
+			}
+			verify.Values(t, "data", got, data)
+		})
+	}
+
+	t.Run("unsupported protocol class", func(t *testing.T) {
+		if _, err := s.Segment(2, true, 15, cdpa, cgpa, make([]byte, 1000)); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		if _, err := s.Segment(0, true, 15, cdpa, cgpa, make([]byte, 16*255)); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
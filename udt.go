@@ -99,8 +99,13 @@ func (u *UDT) MarshalTo(b []byte) error {
 
 // ParseUDT decodes given byte sequence as a SCCP UDT.
 func ParseUDT(b []byte) (*UDT, error) {
+	return ParseUDTWithVariant(params.VariantITU, b)
+}
+
+// ParseUDTWithVariant decodes given byte sequence as a SCCP UDT in the given protocol variant.
+func ParseUDTWithVariant(v params.Variant, b []byte) (*UDT, error) {
 	u := &UDT{}
-	if err := u.UnmarshalBinary(b); err != nil {
+	if err := u.unmarshalBinaryWithVariant(v, b); err != nil {
 		return nil, err
 	}
 
@@ -109,6 +114,12 @@ func ParseUDT(b []byte) (*UDT, error) {
 
 // UnmarshalBinary sets the values retrieved from byte sequence in a SCCP UDT.
 func (u *UDT) UnmarshalBinary(b []byte) error {
+	return u.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP UDT,
+// decoding the parameters in the given protocol variant.
+func (u *UDT) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
 	l := len(b)
 	if l <= 5 {
 		return io.ErrUnexpectedEOF
@@ -153,12 +164,12 @@ func (u *UDT) UnmarshalBinary(b []byte) error {
 		return io.ErrUnexpectedEOF
 	}
 
-	u.CalledPartyAddress, _, err = params.ParseCalledPartyAddress(b[offsetPtr1:cdpaEnd])
+	u.CalledPartyAddress, _, err = params.ParseCalledPartyAddressWithVariant(v, b[offsetPtr1:cdpaEnd])
 	if err != nil {
 		return err
 	}
 
-	u.CallingPartyAddress, _, err = params.ParseCallingPartyAddress(b[offsetPtr2:cgpaEnd])
+	u.CallingPartyAddress, _, err = params.ParseCallingPartyAddressWithVariant(v, b[offsetPtr2:cgpaEnd])
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,171 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// maxHopCounter is the initial value of the Hop Counter in the service messages.
+const maxHopCounter = 15
+
+// unitdata is the common representation of the connectionless messages, i.e.,
+// UDT, XUDT, LUDT and their service messages, used in the routing.
+type unitdata struct {
+	typ MsgType
+
+	pcls     int
+	retOnErr bool
+	cause    params.ReturnCauseValue // only in the service messages
+	hc       uint8                   // only in XUDT(S) and LUDT(S)
+
+	cdpa *params.PartyAddress
+	cgpa *params.PartyAddress
+	data []byte
+	opts []params.Parameter
+}
+
+// newUnitdata returns the unitdata of the Message, or false if the Message
+// is not a connectionless message.
+func newUnitdata(m Message) (*unitdata, bool) {
+	switch m := m.(type) {
+	case *UDT:
+		return &unitdata{
+			typ:      m.Type,
+			pcls:     m.ProtocolClass.Class(),
+			retOnErr: m.ProtocolClass.ReturnOnError(),
+			cdpa:     m.CalledPartyAddress,
+			cgpa:     m.CallingPartyAddress,
+			data:     m.Data.Value(),
+		}, true
+	case *UDTS:
+		return &unitdata{
+			typ:   m.Type,
+			cause: m.ReturnCause.Value(),
+			cdpa:  m.CalledPartyAddress,
+			cgpa:  m.CallingPartyAddress,
+			data:  m.Data.Value(),
+		}, true
+	case *XUDT:
+		return &unitdata{
+			typ:      m.Type,
+			pcls:     m.ProtocolClass.Class(),
+			retOnErr: m.ProtocolClass.ReturnOnError(),
+			hc:       m.HopCounter.Value(),
+			cdpa:     m.CalledPartyAddress,
+			cgpa:     m.CallingPartyAddress,
+			data:     m.Data.Value(),
+			opts:     optionals(m.Segmentation, m.Importance),
+		}, true
+	case *XUDTS:
+		return &unitdata{
+			typ:   m.Type,
+			cause: m.ReturnCause.Value(),
+			hc:    m.HopCounter.Value(),
+			cdpa:  m.CalledPartyAddress,
+			cgpa:  m.CallingPartyAddress,
+			data:  m.Data.Value(),
+			opts:  optionals(m.Segmentation, m.Importance),
+		}, true
+	case *LUDT:
+		return &unitdata{
+			typ:      m.Type,
+			pcls:     m.ProtocolClass.Class(),
+			retOnErr: m.ProtocolClass.ReturnOnError(),
+			hc:       m.HopCounter.Value(),
+			cdpa:     m.CalledPartyAddress,
+			cgpa:     m.CallingPartyAddress,
+			data:     m.LongData.Value(),
+			opts:     optionals(m.Segmentation, m.Importance),
+		}, true
+	case *LUDTS:
+		return &unitdata{
+			typ:   m.Type,
+			cause: m.ReturnCause.Value(),
+			hc:    m.HopCounter.Value(),
+			cdpa:  m.CalledPartyAddress,
+			cgpa:  m.CallingPartyAddress,
+			data:  m.LongData.Value(),
+			opts:  optionals(m.Segmentation, m.Importance),
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// optionals returns the optional parameters that are present.
+func optionals(seg *params.Segmentation, imp *params.Importance) []params.Parameter {
+	var opts []params.Parameter
+	if seg != nil {
+		opts = append(opts, seg)
+	}
+	if imp != nil {
+		opts = append(opts, imp)
+	}
+	return opts
+}
+
+// isService reports whether the unitdata is a service message.
+func (u *unitdata) isService() bool {
+	switch u.typ {
+	case MsgTypeUDTS, MsgTypeXUDTS, MsgTypeLUDTS:
+		return true
+	default:
+		return false
+	}
+}
+
+// message creates the Message from the unitdata.
+//
+// The Message is created with the constructor so that the pointers are set
+// properly for the current addresses.
+func (u *unitdata) message() Message {
+	switch u.typ {
+	case MsgTypeUDT:
+		return NewUDT(u.pcls, u.retOnErr, u.cdpa, u.cgpa, u.data)
+	case MsgTypeUDTS:
+		return NewUDTS(u.cause, u.cdpa, u.cgpa, u.data)
+	case MsgTypeXUDT:
+		return NewXUDT(u.pcls, u.retOnErr, u.hc, u.cdpa, u.cgpa, u.data, u.opts...)
+	case MsgTypeXUDTS:
+		data := u.data
+		if len(u.opts) > 0 {
+			// the returned data are truncated so that the optional part can be pointed,
+			// as the addresses can be longer than the ones in the original XUDT.
+			x := NewXUDTS(u.cause, u.hc, u.cdpa, u.cgpa, nil, u.opts...)
+			data = data[:min(len(data), 0xff-int(x.ptr4))]
+		}
+		return NewXUDTS(u.cause, u.hc, u.cdpa, u.cgpa, data, u.opts...)
+	case MsgTypeLUDT:
+		return NewLUDT(u.pcls, u.retOnErr, u.hc, u.cdpa, u.cgpa, u.data, u.opts...)
+	case MsgTypeLUDTS:
+		return NewLUDTS(u.cause, u.hc, u.cdpa, u.cgpa, u.data, u.opts...)
+	default:
+		return nil
+	}
+}
+
+// service returns the service message to be returned to the originator of the unitdata.
+func (u *unitdata) service(cause params.ReturnCauseValue) *unitdata {
+	s := &unitdata{
+		cause: cause,
+		hc:    maxHopCounter,
+		cdpa:  u.cgpa,
+		cgpa:  u.cdpa,
+		data:  u.data,
+		opts:  u.opts,
+	}
+
+	switch u.typ {
+	case MsgTypeUDT:
+		s.typ = MsgTypeUDTS
+	case MsgTypeXUDT:
+		s.typ = MsgTypeXUDTS
+	case MsgTypeLUDT:
+		s.typ = MsgTypeLUDTS
+	}
+
+	return s
+}
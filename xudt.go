@@ -102,22 +102,22 @@ func (x *XUDT) MarshalTo(b []byte) error {
 		return io.ErrUnexpectedEOF
 	}
 	b[n+1] = x.ptr2
-	if p := int(x.ptr2 + 4); l < p {
+	if p := int(x.ptr2) + 4; l < p {
 		return io.ErrUnexpectedEOF
 	}
 	b[n+2] = x.ptr3
-	if p := int(x.ptr3 + 5); l < p {
+	if p := int(x.ptr3) + 5; l < p {
 		return io.ErrUnexpectedEOF
 	}
 	b[n+3] = x.ptr4
-	if p := int(x.ptr4 + 6); l < p {
+	if p := int(x.ptr4) + 6; l < p {
 		return io.ErrUnexpectedEOF
 	}
 	n += 4
 
-	cdpaEnd := int(x.ptr2 + 4)
-	cgpaEnd := int(x.ptr3 + 5)
-	dataEnd := int(x.ptr4 + 6)
+	cdpaEnd := int(x.ptr2) + 4
+	cgpaEnd := int(x.ptr3) + 5
+	dataEnd := int(x.ptr4) + 6
 	if _, err := x.CalledPartyAddress.Write(b[n:cdpaEnd]); err != nil {
 		return err
 	}
@@ -161,8 +161,13 @@ func (x *XUDT) MarshalTo(b []byte) error {
 
 // ParseXUDT decodes given byte sequence as a SCCP XUDT.
 func ParseXUDT(b []byte) (*XUDT, error) {
+	return ParseXUDTWithVariant(params.VariantITU, b)
+}
+
+// ParseXUDTWithVariant decodes given byte sequence as a SCCP XUDT in the given protocol variant.
+func ParseXUDTWithVariant(v params.Variant, b []byte) (*XUDT, error) {
 	x := &XUDT{}
-	if err := x.UnmarshalBinary(b); err != nil {
+	if err := x.unmarshalBinaryWithVariant(v, b); err != nil {
 		return nil, err
 	}
 
@@ -171,6 +176,12 @@ func ParseXUDT(b []byte) (*XUDT, error) {
 
 // UnmarshalBinary sets the values retrieved from byte sequence in a SCCP XUDT.
 func (x *XUDT) UnmarshalBinary(b []byte) error {
+	return x.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP XUDT,
+// decoding the parameters in the given protocol variant.
+func (x *XUDT) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
 	l := len(b)
 	if l <= 5 {
 		return io.ErrUnexpectedEOF
@@ -227,12 +238,12 @@ func (x *XUDT) UnmarshalBinary(b []byte) error {
 		return io.ErrUnexpectedEOF
 	}
 
-	x.CalledPartyAddress, _, err = params.ParseCalledPartyAddress(b[offsetPtr1:cdpaEnd])
+	x.CalledPartyAddress, _, err = params.ParseCalledPartyAddressWithVariant(v, b[offsetPtr1:cdpaEnd])
 	if err != nil {
 		return err
 	}
 
-	x.CallingPartyAddress, _, err = params.ParseCallingPartyAddress(b[offsetPtr2:cgpaEnd])
+	x.CallingPartyAddress, _, err = params.ParseCallingPartyAddressWithVariant(v, b[offsetPtr2:cgpaEnd])
 	if err != nil {
 		return err
 	}
@@ -246,7 +257,7 @@ func (x *XUDT) UnmarshalBinary(b []byte) error {
 		return nil
 	}
 
-	opts, _, err := params.ParseOptionalParameters(b[offsetPtr4:])
+	opts, _, err := params.ParseOptionalParametersWithVariant(v, b[offsetPtr4:])
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,352 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// XUDTS represents a SCCP Message Extended Unitdata Service (XUDTS).
+type XUDTS struct {
+	Type                    MsgType
+	ReturnCause             *params.ReturnCause
+	HopCounter              *params.HopCounter
+	CalledPartyAddress      *params.PartyAddress
+	CallingPartyAddress     *params.PartyAddress
+	Data                    *params.Data
+	Segmentation            *params.Segmentation
+	Importance              *params.Importance
+	EndOfOptionalParameters *params.EndOfOptionalParameters
+
+	ptr1, ptr2, ptr3, ptr4 uint8
+}
+
+// NewXUDTS creates a new XUDTS.
+func NewXUDTS(cause params.ReturnCauseValue, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) *XUDTS {
+	x := &XUDTS{
+		Type:                MsgTypeXUDTS,
+		ReturnCause:         params.NewCause(cause),
+		HopCounter:          params.NewHopCounter(hc),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Data:                params.NewData(data),
+	}
+
+	x.ptr1 = 4
+	x.ptr2 = x.ptr1 + uint8(cdpa.MarshalLen()) - 1
+	x.ptr3 = x.ptr2 + uint8(cgpa.MarshalLen()) - 1
+	x.ptr4 = 0
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			x.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			x.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			x.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			logf("unexpected parameter: %s in NewXUDTS", opt.Code())
+		}
+	}
+
+	if len(opts) > 0 {
+		x.ptr4 = x.ptr3 + uint8(x.Data.MarshalLen()) - 1
+		// so that users don't have to give EndOfOptionalParameters explicitly
+		x.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
+	}
+
+	return x
+}
+
+// MarshalBinary returns the byte sequence generated from a XUDTS instance.
+func (x *XUDTS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, x.MarshalLen())
+	if err := x.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+func (x *XUDTS) MarshalTo(b []byte) error {
+	l := len(b)
+	if l < 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(x.Type)
+
+	n := 1
+	m, err := x.ReturnCause.Write(b[1:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	m, err = x.HopCounter.Write(b[n:])
+	if err != nil {
+		return err
+	}
+	n += m
+
+	b[n] = x.ptr1
+	if p := int(x.ptr1); l < p {
+		return io.ErrUnexpectedEOF
+	}
+	b[n+1] = x.ptr2
+	if p := int(x.ptr2) + 4; l < p {
+		return io.ErrUnexpectedEOF
+	}
+	b[n+2] = x.ptr3
+	if p := int(x.ptr3) + 5; l < p {
+		return io.ErrUnexpectedEOF
+	}
+	b[n+3] = x.ptr4
+	if p := int(x.ptr4) + 6; l < p {
+		return io.ErrUnexpectedEOF
+	}
+	n += 4
+
+	cdpaEnd := int(x.ptr2) + 4
+	cgpaEnd := int(x.ptr3) + 5
+	dataEnd := int(x.ptr4) + 6
+	if _, err := x.CalledPartyAddress.Write(b[n:cdpaEnd]); err != nil {
+		return err
+	}
+
+	if _, err := x.CallingPartyAddress.Write(b[cdpaEnd:cgpaEnd]); err != nil {
+		return err
+	}
+
+	if _, err := x.Data.Write(b[cgpaEnd:]); err != nil {
+		return err
+	}
+
+	if x.ptr4 == 0 {
+		return nil
+	}
+
+	offset := dataEnd
+	if param := x.Segmentation; param != nil {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+	if param := x.Importance; param != nil {
+		m, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+	if param := x.EndOfOptionalParameters; param != nil {
+		_, err := param.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseXUDTS decodes given byte sequence as a SCCP XUDTS.
+func ParseXUDTS(b []byte) (*XUDTS, error) {
+	return ParseXUDTSWithVariant(params.VariantITU, b)
+}
+
+// ParseXUDTSWithVariant decodes given byte sequence as a SCCP XUDTS in the given protocol variant.
+func ParseXUDTSWithVariant(v params.Variant, b []byte) (*XUDTS, error) {
+	x := &XUDTS{}
+	if err := x.unmarshalBinaryWithVariant(v, b); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP XUDTS.
+func (x *XUDTS) UnmarshalBinary(b []byte) error {
+	return x.unmarshalBinaryWithVariant(params.VariantITU, b)
+}
+
+// unmarshalBinaryWithVariant sets the values retrieved from byte sequence in a SCCP XUDTS,
+// decoding the parameters in the given protocol variant.
+func (x *XUDTS) unmarshalBinaryWithVariant(v params.Variant, b []byte) error {
+	l := len(b)
+	if l < 7 { // where the four pointers end
+		return io.ErrUnexpectedEOF
+	}
+
+	x.Type = MsgType(b[0])
+
+	offset := 1
+	x.ReturnCause = &params.ReturnCause{}
+	n, err := x.ReturnCause.Read(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	x.HopCounter = &params.HopCounter{}
+	n, err = x.HopCounter.Read(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += n
+
+	x.ptr1 = b[offset]
+	offsetPtr1 := 3 + int(x.ptr1)
+	if l < offsetPtr1+1 { // where CdPA starts
+		return io.ErrUnexpectedEOF
+	}
+	x.ptr2 = b[offset+1]
+	offsetPtr2 := 4 + int(x.ptr2)
+	if l < offsetPtr2+1 { // where CgPA starts
+		return io.ErrUnexpectedEOF
+	}
+	x.ptr3 = b[offset+2]
+	offsetPtr3 := 5 + int(x.ptr3)
+	if l < offsetPtr3+1 { // where Data starts
+		return io.ErrUnexpectedEOF
+	}
+	x.ptr4 = b[offset+3]
+	offsetPtr4 := 6 + int(x.ptr4)             // Optional params have a parameter name preceding its length and value, so we cannot take the first byte as a length
+	if m := offsetPtr4; (m != 0) && l < m+1 { // where optional parameters start
+		return io.ErrUnexpectedEOF
+	}
+
+	cdpaEnd := offsetPtr1 + int(b[offsetPtr1]) + 1 // +1 is the data length included from the beginning
+	if l < cdpaEnd {                               // where CdPA ends
+		return io.ErrUnexpectedEOF
+	}
+	cgpaEnd := offsetPtr2 + int(b[offsetPtr2]) + 1
+	if l < cgpaEnd { // where CgPA ends
+		return io.ErrUnexpectedEOF
+	}
+	dataEnd := offsetPtr3 + int(b[offsetPtr3]) + 1
+	if l < dataEnd { // where Data ends
+		return io.ErrUnexpectedEOF
+	}
+
+	// the parameters must be placed in the order without gaps as the pointers are
+	// reused in MarshalTo.
+	if offsetPtr1 != offset+4 || offsetPtr2 != cdpaEnd || offsetPtr3 != cgpaEnd ||
+		(x.ptr4 != 0 && offsetPtr4 != dataEnd) {
+		return io.ErrUnexpectedEOF
+	}
+
+	x.CalledPartyAddress, _, err = params.ParseCalledPartyAddressWithVariant(v, b[offsetPtr1:cdpaEnd])
+	if err != nil {
+		return err
+	}
+
+	x.CallingPartyAddress, _, err = params.ParseCallingPartyAddressWithVariant(v, b[offsetPtr2:cgpaEnd])
+	if err != nil {
+		return err
+	}
+
+	x.Data, _, err = params.ParseData(b[offsetPtr3:dataEnd])
+	if err != nil {
+		return err
+	}
+
+	if x.ptr4 == 0 {
+		return nil
+	}
+
+	opts, _, err := params.ParseOptionalParametersWithVariant(v, b[offsetPtr4:])
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			x.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			x.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			x.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length.
+func (x *XUDTS) MarshalLen() int {
+	l := 7 // MsgType + ReturnCause + HopCounter + Pointers
+
+	// if optional parameters exist
+	if x.ptr4 != 0 {
+		l += int(x.ptr4) - 1 // length without optional parameters
+		if param := x.Segmentation; param != nil {
+			l += param.MarshalLen()
+		}
+		if param := x.Importance; param != nil {
+			l += param.MarshalLen()
+		}
+		if param := x.EndOfOptionalParameters; param != nil {
+			l += param.MarshalLen()
+		}
+
+		return l
+	}
+
+	l += int(x.ptr3) - 2 // length without Data
+	if param := x.Data; param != nil {
+		l += param.MarshalLen()
+	}
+
+	return l
+}
+
+// String returns the XUDTS values in human readable format.
+func (x *XUDTS) String() string {
+	return fmt.Sprintf("%s: {ReturnCause: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %s, Importance: %s}",
+		x.Type,
+		x.ReturnCause,
+		x.HopCounter,
+		x.CalledPartyAddress,
+		x.CallingPartyAddress,
+		x.Data,
+		x.Segmentation,
+		x.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (x *XUDTS) MessageType() MsgType {
+	return MsgTypeXUDTS
+}
+
+// MessageTypeName returns the Message Type in string.
+func (x *XUDTS) MessageTypeName() string {
+	return x.MessageType().String()
+}
+
+// CdGT returns the GT in CalledPartyAddress in human readable string.
+func (x *XUDTS) CdGT() string {
+	if x.CalledPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return x.CalledPartyAddress.Address()
+}
+
+// CgGT returns the GT in CalledPartyAddress in human readable string.
+func (x *XUDTS) CgGT() string {
+	if x.CallingPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return x.CallingPartyAddress.Address()
+}